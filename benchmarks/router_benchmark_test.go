@@ -1,6 +1,7 @@
 package benchmarks
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
@@ -176,3 +177,56 @@ func BenchmarkMemoryAllocations(b *testing.B) {
 		r.ServeHTTP(w, req)
 	}
 }
+
+// benchmarkStaticMatcher benchmarks a lookup among 100 static routes
+func benchmarkStaticMatcher(b *testing.B, opts ...cosan.Option) {
+	r := cosan.New(opts...)
+	for i := 0; i < 100; i++ {
+		r.GET(fmt.Sprintf("/api/resource%03d/items", i), func(ctx cosan.Context) error {
+			return nil
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/api/resource073/items", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
+
+// BenchmarkStaticRoutes100_Radix benchmarks the default radix matcher
+func BenchmarkStaticRoutes100_Radix(b *testing.B) {
+	benchmarkStaticMatcher(b)
+}
+
+// BenchmarkStaticRoutes100_Hash benchmarks the hash matcher
+func BenchmarkStaticRoutes100_Hash(b *testing.B) {
+	benchmarkStaticMatcher(b, cosan.WithMatcher(cosan.NewHashMatcher()))
+}
+
+// BenchmarkQueryParams benchmarks a handler reading several query parameters
+func BenchmarkQueryParams(b *testing.B) {
+	r := cosan.New()
+	r.GET("/search", func(ctx cosan.Context) error {
+		q := ctx.Query("q")
+		page := ctx.QueryIntDefault("page", 1)
+		limit := ctx.QueryIntDefault("limit", 20)
+		sort := ctx.QueryDefault("sort", "relevance")
+		tags := ctx.QueryAll("tag")
+		return ctx.String(200, "%s %d %d %s %d", q, page, limit, sort, len(tags))
+	})
+
+	req := httptest.NewRequest("GET", "/search?q=router&page=2&limit=50&sort=date&tag=go&tag=http", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
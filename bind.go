@@ -0,0 +1,137 @@
+package cosan
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// errNotStructPointer is returned when a bind target is not a pointer to a struct.
+var errNotStructPointer = errors.New("cosan: bind target must be a non-nil pointer to a struct")
+
+// valueLookup returns the raw values for a tag key and whether the key was present.
+type valueLookup func(key string) ([]string, bool)
+
+// bindTagged populates the fields of the struct pointed to by v that carry
+// the given tag (e.g. `query:"page"`), converting the values returned by
+// lookup. Fields whose key is absent are left untouched.
+//
+// Supported field types are strings, bools, integers, floats, types
+// implementing encoding.TextUnmarshaler, and pointers or slices of those.
+// Slices receive every value; other types use the first.
+func bindTagged(v interface{}, tag string, lookup valueLookup) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errNotStructPointer
+	}
+
+	return bindStruct(rv.Elem(), tag, lookup)
+}
+
+// bindStruct binds the fields of a struct value, descending into embedded structs.
+func bindStruct(sv reflect.Value, tag string, lookup valueLookup) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		fv := sv.Field(i)
+
+		key, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if key == "" {
+			if field.Anonymous && fv.Kind() == reflect.Struct {
+				if err := bindStruct(fv, tag, lookup); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if key == "-" || !field.IsExported() {
+			continue
+		}
+
+		values, ok := lookup(key)
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		if err := setField(fv, values); err != nil {
+			return &BindError{Field: field.Name, Key: key, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// setField converts values into the field.
+func setField(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Slice && !implementsTextUnmarshaler(fv) {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	return setValue(fv, values[0])
+}
+
+// setValue converts a single string into the value.
+func setValue(fv reflect.Value, value string) error {
+	if fv.Kind() == reflect.Ptr {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	if implementsTextUnmarshaler(fv) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return errors.New("unsupported field type " + fv.Type().String())
+	}
+
+	return nil
+}
+
+// implementsTextUnmarshaler reports whether a pointer to the value implements
+// encoding.TextUnmarshaler.
+func implementsTextUnmarshaler(fv reflect.Value) bool {
+	return fv.CanAddr() && reflect.PointerTo(fv.Type()).Implements(textUnmarshalerType)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
@@ -0,0 +1,285 @@
+package cosan
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type upperString string
+
+func (u *upperString) UnmarshalText(b []byte) error {
+	*u = upperString(strings.ToUpper(string(b)))
+	return nil
+}
+
+func TestContext_BindHeader(t *testing.T) {
+	type Meta struct {
+		RequestID string `header:"x-request-id"`
+	}
+	type Headers struct {
+		Meta
+		Tenant     string      `header:"X-Tenant-ID"`
+		APIVersion int         `header:"X-API-Version"`
+		DryRun     bool        `header:"X-Dry-Run"`
+		Idempotent *string     `header:"Idempotency-Key"`
+		Accept     []string    `header:"Accept"`
+		Region     upperString `header:"X-Region"`
+		Missing    string      `header:"X-Missing"`
+		Ignored    string      `header:"-"`
+	}
+
+	req := httptest.NewRequest("POST", "/orders", nil)
+	req.Header.Set("X-Tenant-Id", "acme")
+	req.Header.Set("X-Api-Version", "2")
+	req.Header.Set("X-Dry-Run", "true")
+	req.Header.Set("Idempotency-Key", "abc-123")
+	req.Header.Set("X-Request-Id", "req-1")
+	req.Header.Set("X-Region", "eu-west")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Accept", "text/plain")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	h := Headers{Missing: "keep"}
+	if err := ctx.BindHeader(&h); err != nil {
+		t.Fatalf("BindHeader failed: %v", err)
+	}
+
+	if h.Tenant != "acme" || h.APIVersion != 2 || !h.DryRun {
+		t.Errorf("Unexpected scalar values: %+v", h)
+	}
+	if h.Idempotent == nil || *h.Idempotent != "abc-123" {
+		t.Errorf("Expected pointer field to be set, got %v", h.Idempotent)
+	}
+	if len(h.Accept) != 2 || h.Accept[1] != "text/plain" {
+		t.Errorf("Expected both Accept values, got %v", h.Accept)
+	}
+	if h.RequestID != "req-1" {
+		t.Errorf("Expected embedded field to be bound, got %q", h.RequestID)
+	}
+	if h.Region != "EU-WEST" {
+		t.Errorf("Expected TextUnmarshaler to be used, got %q", h.Region)
+	}
+	if h.Missing != "keep" {
+		t.Errorf("Missing headers should leave fields untouched, got %q", h.Missing)
+	}
+}
+
+func TestContext_BindQuery(t *testing.T) {
+	type Search struct {
+		Q     string      `query:"q"`
+		Page  int         `query:"page"`
+		Score float64     `query:"score"`
+		Tags  []string    `query:"tag"`
+		IDs   []uint      `query:"id"`
+		Limit *int        `query:"limit"`
+		Order upperString `query:"order"`
+	}
+
+	req := httptest.NewRequest("GET", "/search?q=go&page=2&score=0.5&tag=a&tag=b&id=1&id=2&order=desc", nil)
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	var s Search
+	if err := ctx.BindQuery(&s); err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	if s.Q != "go" || s.Page != 2 || s.Score != 0.5 || s.Order != "DESC" {
+		t.Errorf("Unexpected values: %+v", s)
+	}
+	if len(s.Tags) != 2 || len(s.IDs) != 2 || s.IDs[1] != 2 {
+		t.Errorf("Unexpected slices: %v %v", s.Tags, s.IDs)
+	}
+	if s.Limit != nil {
+		t.Errorf("Expected nil pointer for missing key, got %v", *s.Limit)
+	}
+}
+
+func TestContext_BindConversionError(t *testing.T) {
+	type Headers struct {
+		Version int `header:"X-API-Version"`
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-API-Version", "two")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	var h Headers
+	err := ctx.BindHeader(&h)
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("Expected *BindError, got %v", err)
+	}
+	if bindErr.Field != "Version" || bindErr.Key != "X-API-Version" {
+		t.Errorf("Unexpected error details: %+v", bindErr)
+	}
+}
+
+func TestContext_BindParams(t *testing.T) {
+	type PostRef struct {
+		UserID int    `param:"id"`
+		PostID int    `param:"postId"`
+		Slug   string `param:"slug"`
+		Trace  string `param:"trace"`
+		Other  string
+	}
+
+	var bound PostRef
+	var bindErr error
+	r := New()
+	r.GET("/users/:id/posts/:postId/:slug/:trace", func(ctx Context) error {
+		bound = PostRef{}
+		bindErr = ctx.BindParams(&bound)
+		return nil
+	})
+
+	serve := func(path string) {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	serve("/users/42/posts/7/hello%20world/3f2b8c1e-9a4d-4f6e-8b7a-1c2d3e4f5a6b")
+	if bindErr != nil {
+		t.Fatalf("BindParams failed: %v", bindErr)
+	}
+	want := PostRef{UserID: 42, PostID: 7, Slug: "hello world", Trace: "3f2b8c1e-9a4d-4f6e-8b7a-1c2d3e4f5a6b"}
+	if bound != want {
+		t.Errorf("Expected %+v, got %+v", want, bound)
+	}
+
+	serve("/users/42/posts/latest/hello/abc")
+	var be *BindError
+	if !errors.As(bindErr, &be) {
+		t.Fatalf("Expected *BindError, got %v", bindErr)
+	}
+	if be.Field != "PostID" || be.Key != "postId" {
+		t.Errorf("Unexpected error details: %+v", be)
+	}
+}
+
+func TestContext_BindInvalidTarget(t *testing.T) {
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+
+	var s struct{}
+	for _, target := range []interface{}{nil, s, new(int)} {
+		if err := ctx.BindQuery(target); err == nil {
+			t.Errorf("Expected error binding into %T", target)
+		}
+	}
+}
+
+func TestContext_BindAll(t *testing.T) {
+	type Update struct {
+		ID       int    `json:"id" param:"id"`
+		Notify   bool   `json:"notify" query:"notify"`
+		Tenant   string `json:"tenant" header:"X-Tenant-ID" query:"tenant"`
+		Name     string `json:"name"`
+		Priority string `json:"priority" header:"X-Priority"`
+	}
+
+	body := `{"id": 1, "notify": false, "tenant": "body", "name": "Ada", "priority": "low"}`
+	req := httptest.NewRequest("PUT", "/users/42/profile?notify=true&tenant=query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", "header")
+	req.Header.Set("X-Priority", "high")
+	ctx := newContext(httptest.NewRecorder(), req, map[string]string{"id": "42"})
+
+	var u Update
+	if err := ctx.BindAll(&u); err != nil {
+		t.Fatalf("BindAll failed: %v", err)
+	}
+
+	if u.Name != "Ada" {
+		t.Errorf("Expected body-only field, got %q", u.Name)
+	}
+	if u.Priority != "high" {
+		t.Errorf("Expected header to override body, got %q", u.Priority)
+	}
+	if u.Tenant != "query" {
+		t.Errorf("Expected query to override header and body, got %q", u.Tenant)
+	}
+	if !u.Notify {
+		t.Error("Expected query to override body for notify")
+	}
+	if u.ID != 42 {
+		t.Errorf("Expected path param to override body, got %d", u.ID)
+	}
+}
+
+func TestContext_BindAllWithoutBody(t *testing.T) {
+	type Lookup struct {
+		ID int `param:"id"`
+	}
+
+	req := httptest.NewRequest("GET", "/users/7", nil)
+	ctx := newContext(httptest.NewRecorder(), req, map[string]string{"id": "7"})
+
+	var l Lookup
+	if err := ctx.BindAll(&l); err != nil {
+		t.Fatalf("BindAll without body failed: %v", err)
+	}
+	if l.ID != 7 {
+		t.Errorf("Expected ID 7, got %d", l.ID)
+	}
+}
+
+func TestWithMaxBindBytes(t *testing.T) {
+	bind := func(ctx Context) error {
+		var v struct {
+			Name string `json:"name"`
+		}
+		if err := ctx.Bind(&v); err != nil {
+			return err
+		}
+		return ctx.String(200, "%s", v.Name)
+	}
+
+	r := New(WithMaxBindBytes(32))
+	r.POST("/small", bind)
+	r.POST("/large", bind, WithBindLimit(1024))
+
+	unlimited := New(WithMaxBindBytes(0))
+	unlimited.POST("/small", bind)
+
+	short := `{"name":"alice"}`
+	long := `{"name":"` + strings.Repeat("a", 64) + `"}`
+
+	tests := []struct {
+		name    string
+		router  Router
+		path    string
+		body    string
+		chunked bool
+		code    int
+	}{
+		{"under limit", r, "/small", short, false, 200},
+		{"over limit", r, "/small", long, false, 413},
+		{"over limit without Content-Length", r, "/small", long, true, 413},
+		{"raised per route", r, "/large", long, false, 200},
+		{"disabled", unlimited, "/small", long, false, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.chunked {
+				// Hide the length so the limit is enforced while reading
+				body = io.MultiReader(body)
+			}
+			req := httptest.NewRequest("POST", tt.path, body)
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			tt.router.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Errorf("Expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if New().(*router).maxBindBytes != DefaultMaxBindBytes {
+		t.Errorf("Expected the default limit to be DefaultMaxBindBytes")
+	}
+}
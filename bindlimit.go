@@ -0,0 +1,85 @@
+package cosan
+
+import (
+	"errors"
+	"net/http"
+)
+
+// DefaultMaxBindBytes is the default limit on the request body Bind reads.
+const DefaultMaxBindBytes = 1 << 20
+
+// WithMaxBindBytes limits the request body Bind, BindStrict, and BindAll
+// read to n bytes (default DefaultMaxBindBytes), so an oversized body
+// cannot exhaust memory even without a body-limit middleware. A larger body
+// fails with an *HTTPError 413. Routes that accept bigger payloads raise
+// their own limit with WithBindLimit; n <= 0 disables the limit.
+//
+// The limit applies only to binding: BodyBytes and Request().Body read the
+// body without it.
+func WithMaxBindBytes(n int64) Option {
+	return func(r *router) {
+		r.maxBindBytes = n
+	}
+}
+
+// WithBindLimit overrides WithMaxBindBytes for the route; n <= 0 disables
+// the limit.
+//
+// Example:
+//
+//	router.POST("/imports", ImportHandler, cosan.WithBindLimit(50<<20))
+func WithBindLimit(n int64) RouteOption {
+	return func(r *route) {
+		r.maxBindBytes = n
+		r.maxBindBytesSet = true
+	}
+}
+
+// maxBindBytes returns the body limit for Bind: the route's, else the
+// router's.
+func (c *context) maxBindBytes() int64 {
+	if c.route != nil && c.route.maxBindBytesSet {
+		return c.route.maxBindBytes
+	}
+	if c.router == nil {
+		return DefaultMaxBindBytes
+	}
+	return c.router.maxBindBytes
+}
+
+// bindBody returns the request body for binding, enforcing maxBindBytes.
+// A body declared or found to be larger yields an *HTTPError 413 without
+// reading past the limit.
+func (c *context) bindBody() ([]byte, error) {
+	limit := c.maxBindBytes()
+	if limit <= 0 {
+		return c.BodyBytes()
+	}
+
+	if c.bodyRead {
+		if int64(len(c.body)) > limit {
+			return nil, bodyTooLarge(nil)
+		}
+		return c.body, nil
+	}
+	if c.req.ContentLength > limit {
+		return nil, bodyTooLarge(nil)
+	}
+
+	c.req.Body = http.MaxBytesReader(c.res, c.req.Body, limit)
+	body, err := c.BodyBytes()
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return nil, bodyTooLarge(err)
+	}
+	return body, err
+}
+
+// bodyTooLarge returns the 413 error for a body over the bind limit.
+func bodyTooLarge(err error) error {
+	return &HTTPError{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: http.StatusText(http.StatusRequestEntityTooLarge),
+		Err:     err,
+	}
+}
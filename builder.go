@@ -0,0 +1,114 @@
+package cosan
+
+import "net/http"
+
+// RouteBuilder registers handlers for several methods on one pattern.
+// It is returned by Router.Route.
+//
+// Example:
+//
+//	router.Route("/users/:id").
+//		Use(LoadUser).
+//		GET(GetUser).
+//		PUT(UpdateUser).
+//		DELETE(DeleteUser)
+type RouteBuilder interface {
+	// Use adds middleware to the handlers registered after it on this
+	// builder. It runs inside the global middleware.
+	Use(middleware ...Middleware) RouteBuilder
+
+	// GET registers a handler for GET requests on the builder's pattern.
+	GET(handler HandlerFunc, opts ...RouteOption) RouteBuilder
+
+	// POST registers a handler for POST requests on the builder's pattern.
+	POST(handler HandlerFunc, opts ...RouteOption) RouteBuilder
+
+	// PUT registers a handler for PUT requests on the builder's pattern.
+	PUT(handler HandlerFunc, opts ...RouteOption) RouteBuilder
+
+	// DELETE registers a handler for DELETE requests on the builder's pattern.
+	DELETE(handler HandlerFunc, opts ...RouteOption) RouteBuilder
+
+	// PATCH registers a handler for PATCH requests on the builder's pattern.
+	PATCH(handler HandlerFunc, opts ...RouteOption) RouteBuilder
+
+	// OPTIONS registers a handler for OPTIONS requests on the builder's pattern.
+	OPTIONS(handler HandlerFunc, opts ...RouteOption) RouteBuilder
+
+	// HEAD registers a handler for HEAD requests on the builder's pattern.
+	HEAD(handler HandlerFunc, opts ...RouteOption) RouteBuilder
+}
+
+// routeBuilder implements RouteBuilder on top of a register function, so
+// the router and groups share it.
+type routeBuilder struct {
+	register   func(method, pattern string, handler HandlerFunc, opts []RouteOption)
+	pattern    string
+	middleware []Middleware
+}
+
+// Route returns a builder that registers handlers for several methods on
+// pattern, with optional middleware scoped to them.
+func (r *router) Route(pattern string) RouteBuilder {
+	return &routeBuilder{
+		register: func(method, pattern string, handler HandlerFunc, opts []RouteOption) {
+			r.registerRoute(method, pattern, handler, opts...)
+		},
+		pattern: pattern,
+	}
+}
+
+// Route returns a builder for pattern within the group.
+func (g *routerGroup) Route(pattern string) RouteBuilder {
+	return &routeBuilder{register: g.register, pattern: pattern}
+}
+
+func (b *routeBuilder) Use(middleware ...Middleware) RouteBuilder {
+	b.middleware = append(b.middleware[:len(b.middleware):len(b.middleware)], middleware...)
+	return b
+}
+
+func (b *routeBuilder) GET(handler HandlerFunc, opts ...RouteOption) RouteBuilder {
+	return b.handle(http.MethodGet, handler, opts)
+}
+
+func (b *routeBuilder) POST(handler HandlerFunc, opts ...RouteOption) RouteBuilder {
+	return b.handle(http.MethodPost, handler, opts)
+}
+
+func (b *routeBuilder) PUT(handler HandlerFunc, opts ...RouteOption) RouteBuilder {
+	return b.handle(http.MethodPut, handler, opts)
+}
+
+func (b *routeBuilder) DELETE(handler HandlerFunc, opts ...RouteOption) RouteBuilder {
+	return b.handle(http.MethodDelete, handler, opts)
+}
+
+func (b *routeBuilder) PATCH(handler HandlerFunc, opts ...RouteOption) RouteBuilder {
+	return b.handle(http.MethodPatch, handler, opts)
+}
+
+func (b *routeBuilder) OPTIONS(handler HandlerFunc, opts ...RouteOption) RouteBuilder {
+	return b.handle(http.MethodOptions, handler, opts)
+}
+
+func (b *routeBuilder) HEAD(handler HandlerFunc, opts ...RouteOption) RouteBuilder {
+	return b.handle(http.MethodHead, handler, opts)
+}
+
+// handle registers handler for method, attaching the builder's middleware.
+func (b *routeBuilder) handle(method string, handler HandlerFunc, opts []RouteOption) RouteBuilder {
+	if len(b.middleware) > 0 {
+		opts = append([]RouteOption{withMiddleware(b.middleware)}, opts...)
+	}
+	b.register(method, b.pattern, handler, opts)
+	return b
+}
+
+// withMiddleware attaches route-scoped middleware, applied inside the
+// global middleware when the route is compiled.
+func withMiddleware(middleware []Middleware) RouteOption {
+	return func(r *route) {
+		r.middleware = middleware
+	}
+}
@@ -0,0 +1,69 @@
+package cosan
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouteBuilder(t *testing.T) {
+	r := New()
+	var order []string
+	trace := func(name string) Middleware {
+		return MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+			return func(ctx Context) error {
+				order = append(order, name)
+				return next(ctx)
+			}
+		})
+	}
+	r.Use(trace("global"))
+
+	r.Route("/users/:id").
+		GET(func(ctx Context) error { return ctx.String(200, "get %s", ctx.Param("id")) }).
+		Use(trace("resource")).
+		PUT(func(ctx Context) error { return ctx.String(200, "put %s", ctx.Param("id")) }).
+		DELETE(func(ctx Context) error { return ctx.String(200, "delete %s", ctx.Param("id")) })
+	r.POST("/users/:id", func(ctx Context) error { return ctx.String(200, "post") })
+
+	tests := []struct {
+		method string
+		body   string
+		order  string
+	}{
+		{"GET", "get 7", "global"},
+		{"PUT", "put 7", "global,resource"},
+		{"DELETE", "delete 7", "global,resource"},
+		{"POST", "post", "global"},
+	}
+
+	for _, tt := range tests {
+		order = nil
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, "/users/7", nil))
+
+		if w.Code != 200 || w.Body.String() != tt.body {
+			t.Errorf("%s: expected 200 %q, got %d %q", tt.method, tt.body, w.Code, w.Body.String())
+		}
+		if got := strings.Join(order, ","); got != tt.order {
+			t.Errorf("%s: expected middleware %q, got %q", tt.method, tt.order, got)
+		}
+	}
+}
+
+func TestRouteBuilder_Group(t *testing.T) {
+	r := New()
+	r.Group("/api").Route("/items").
+		GET(func(ctx Context) error { return ctx.String(200, "list") }, WithName("items.list")).
+		POST(func(ctx Context) error { return ctx.String(201, "created") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/api/items", nil))
+	if w.Code != 201 {
+		t.Errorf("Expected status 201, got %d", w.Code)
+	}
+
+	if info := r.FindRoute("items.list"); info == nil || info.Method != "GET" || info.Pattern != "/api/items" {
+		t.Errorf("Expected named GET /api/items route, got %+v", info)
+	}
+}
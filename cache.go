@@ -0,0 +1,53 @@
+package cosan
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl adds value, one or more comma-separated directives, to the
+// route's Cache-Control response header, keeping cache policy next to the
+// route instead of in the handler.
+// Options combine in order, so CacheControl("public") with MaxAge(time.Hour)
+// sends "public, max-age=3600".
+//
+// The header is set before the handler runs, so a handler can still
+// replace it. It is dropped when the handler returns an error before
+// responding, so error responses are not cached.
+//
+// Example:
+//
+//	router.GET("/avatars/:id", AvatarHandler, cosan.CacheControl("public, immutable"))
+func CacheControl(value string) RouteOption {
+	return func(r *route) {
+		r.cacheControl = append(r.cacheControl, value)
+	}
+}
+
+// MaxAge adds a max-age directive for d, truncated to whole seconds, to the
+// route's Cache-Control header. See CacheControl.
+//
+// Example:
+//
+//	router.GET("/products", ListProducts, cosan.MaxAge(5*time.Minute))
+func MaxAge(d time.Duration) RouteOption {
+	return CacheControl("max-age=" + strconv.FormatInt(int64(d/time.Second), 10))
+}
+
+// cacheControlHandler sets the route's Cache-Control header before next
+// runs, removing it again if next fails without responding.
+func cacheControlHandler(next HandlerFunc, directives []string) HandlerFunc {
+	value := strings.Join(directives, ", ")
+
+	return func(ctx Context) error {
+		ctx.Header().Set("Cache-Control", value)
+		err := next(ctx)
+		if err != nil {
+			if c, ok := unwrapContext(ctx); ok && !c.committed() {
+				ctx.Header().Del("Cache-Control")
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,44 @@
+package cosan
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheControl(t *testing.T) {
+	r := New()
+	r.GET("/products", func(ctx Context) error {
+		return ctx.String(200, "products")
+	}, CacheControl("public"), MaxAge(5*time.Minute))
+	r.GET("/cart", func(ctx Context) error {
+		return ctx.String(200, "cart")
+	})
+	r.GET("/custom", func(ctx Context) error {
+		ctx.Header().Set("Cache-Control", "no-store")
+		return ctx.String(200, "custom")
+	}, MaxAge(time.Hour))
+	r.GET("/fail", func(ctx Context) error {
+		return errors.New("boom")
+	}, MaxAge(time.Hour))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/products", "public, max-age=300"},
+		{"/cart", ""},
+		{"/custom", "no-store"},
+		{"/fail", ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if got := w.Header().Get("Cache-Control"); got != tt.want {
+			t.Errorf("%s: expected Cache-Control %q, got %q", tt.path, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package cosan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+)
+
+// protobufContentType is the media type of protobuf bodies.
+const protobufContentType = "application/x-protobuf"
+
+// errStrictUnsupported is returned when strict binding is requested but the
+// configured JSON decoder cannot reject unknown fields.
+var errStrictUnsupported = errors.New("cosan: JSON decoder does not support DisallowUnknownFields")
+
+// WithJSONCodec sets the JSON codec used by Context.JSON and Context.Bind.
+// Defaults to encoding/json.
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithJSONCodec(myCodec{}))
+func WithJSONCodec(codec JSONMarshaler) Option {
+	return func(r *router) {
+		r.jsonCodec = codec
+	}
+}
+
+// stdJSONCodec implements JSONMarshaler with encoding/json.
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+// jsonCodec returns the router's JSON codec, or encoding/json.
+func (c *context) jsonCodec() JSONMarshaler {
+	if c.router != nil && c.router.jsonCodec != nil {
+		return c.router.jsonCodec
+	}
+	return stdJSONCodec{}
+}
+
+// WithProtoCodec sets the codec used by Context.Protobuf and by Bind for
+// application/x-protobuf request bodies.
+func WithProtoCodec(codec ProtoMarshaler) Option {
+	return func(r *router) {
+		r.protoCodec = codec
+	}
+}
+
+// protoCodec returns the router's protobuf codec, or nil.
+func (c *context) protoCodec() ProtoMarshaler {
+	if c.router == nil {
+		return nil
+	}
+	return c.router.protoCodec
+}
+
+// Protobuf writes msg as an application/x-protobuf response.
+func (c *context) Protobuf(code int, msg interface{}) error {
+	codec := c.protoCodec()
+	if codec == nil {
+		return ErrNoProtoCodec
+	}
+
+	data, err := codec.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode protobuf: %w", err)
+	}
+
+	return c.Blob(code, protobufContentType, data)
+}
+
+// bindProto decodes a protobuf request body into msg.
+func (c *context) bindProto(msg interface{}) error {
+	codec := c.protoCodec()
+	if codec == nil {
+		return ErrNoProtoCodec
+	}
+
+	body, err := c.bindBody()
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	if err := codec.Unmarshal(body, msg); err != nil {
+		return fmt.Errorf("failed to decode protobuf: %w", err)
+	}
+
+	return nil
+}
+
+// WithMsgPackCodec sets the codec used by Context.MsgPack and by Bind for
+// application/msgpack request bodies.
+func WithMsgPackCodec(codec MsgPackMarshaler) Option {
+	return func(r *router) {
+		r.msgPackCodec = codec
+	}
+}
+
+// msgPackCodec returns the router's MessagePack codec, or nil.
+func (c *context) msgPackCodec() MsgPackMarshaler {
+	if c.router == nil {
+		return nil
+	}
+	return c.router.msgPackCodec
+}
+
+// MsgPack writes v as an application/msgpack response.
+func (c *context) MsgPack(code int, v interface{}) error {
+	codec := c.msgPackCodec()
+	if codec == nil {
+		return ErrNoMsgPackCodec
+	}
+
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode msgpack: %w", err)
+	}
+
+	return c.Blob(code, msgPackContentType, data)
+}
+
+// bindMsgPack decodes a MessagePack request body into v.
+func (c *context) bindMsgPack(v interface{}) error {
+	codec := c.msgPackCodec()
+	if codec == nil {
+		return ErrNoMsgPackCodec
+	}
+
+	body, err := c.bindBody()
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	if err := codec.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode msgpack: %w", err)
+	}
+
+	return nil
+}
+
+// mediaType returns the lower-cased media type of a Content-Type header,
+// without parameters.
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mt
+}
@@ -0,0 +1,181 @@
+package cosan
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// countingCodec wraps encoding/json and counts its calls.
+type countingCodec struct {
+	marshals, decoders int
+	lenient            bool // decoders without DisallowUnknownFields
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) Decoder {
+	c.decoders++
+	if c.lenient {
+		return lenientDecoder{json.NewDecoder(r)}
+	}
+	return json.NewDecoder(r)
+}
+
+type lenientDecoder struct{ d *json.Decoder }
+
+func (l lenientDecoder) Decode(v interface{}) error { return l.d.Decode(v) }
+
+func TestWithJSONCodec(t *testing.T) {
+	codec := &countingCodec{}
+	r := New(WithJSONCodec(codec))
+	r.POST("/echo", func(ctx Context) error {
+		var body map[string]string
+		if err := ctx.Bind(&body); err != nil {
+			return err
+		}
+		return ctx.JSON(200, body)
+	})
+
+	req := httptest.NewRequest("POST", "/echo", strings.NewReader(`{"name":"cosan"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "{\"name\":\"cosan\"}\n" {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+	if codec.marshals != 1 || codec.decoders != 1 {
+		t.Errorf("Expected codec to marshal and decode once, got %d/%d", codec.marshals, codec.decoders)
+	}
+}
+
+func TestWithJSONCodec_StrictUnsupported(t *testing.T) {
+	r := New(WithJSONCodec(&countingCodec{lenient: true}))
+	var bindErr error
+	r.POST("/strict", func(ctx Context) error {
+		var body struct{ Name string }
+		bindErr = ctx.BindStrict(&body)
+		return nil
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/strict", strings.NewReader(`{"Name":"x"}`)))
+
+	if !errors.Is(bindErr, errStrictUnsupported) {
+		t.Errorf("Expected errStrictUnsupported, got %v", bindErr)
+	}
+}
+
+// stubProto stands in for a generated message.
+type stubProto struct{ Name string }
+
+// stubProtoCodec "encodes" a stubProto as its name.
+type stubProtoCodec struct{}
+
+func (stubProtoCodec) Marshal(msg interface{}) ([]byte, error) {
+	return []byte(msg.(*stubProto).Name), nil
+}
+
+func (stubProtoCodec) Unmarshal(data []byte, msg interface{}) error {
+	msg.(*stubProto).Name = string(data)
+	return nil
+}
+
+func TestWithProtoCodec(t *testing.T) {
+	r := New(WithProtoCodec(stubProtoCodec{}))
+	r.POST("/echo", func(ctx Context) error {
+		var msg stubProto
+		if err := ctx.Bind(&msg); err != nil {
+			return err
+		}
+		msg.Name += "!"
+		return ctx.Protobuf(200, &msg)
+	})
+
+	req := httptest.NewRequest("POST", "/echo", strings.NewReader("cosan"))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Body.String() != "cosan!" {
+		t.Errorf("Expected 200 'cosan!', got %d %q", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-protobuf" {
+		t.Errorf("Expected Content-Type application/x-protobuf, got %q", ct)
+	}
+}
+
+func TestProtobufWithoutCodec(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader("x"))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	if err := ctx.Bind(&stubProto{}); !errors.Is(err, ErrNoProtoCodec) {
+		t.Errorf("Bind: expected ErrNoProtoCodec, got %v", err)
+	}
+	if err := ctx.Protobuf(200, &stubProto{}); !errors.Is(err, ErrNoProtoCodec) {
+		t.Errorf("Protobuf: expected ErrNoProtoCodec, got %v", err)
+	}
+}
+
+// stubMsgPackCodec tags JSON with a marker byte so tests can tell the
+// formats apart.
+type stubMsgPackCodec struct{}
+
+func (stubMsgPackCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	return append([]byte{0x80}, data...), err
+}
+
+func (stubMsgPackCodec) Unmarshal(data []byte, v interface{}) error {
+	if len(data) == 0 || data[0] != 0x80 {
+		return errors.New("not msgpack")
+	}
+	return json.Unmarshal(data[1:], v)
+}
+
+func TestWithMsgPackCodec(t *testing.T) {
+	r := New(WithMsgPackCodec(stubMsgPackCodec{}))
+	r.POST("/echo", func(ctx Context) error {
+		var body map[string]string
+		if err := ctx.Bind(&body); err != nil {
+			return err
+		}
+		body["name"] += "!"
+		return ctx.MsgPack(200, body)
+	})
+
+	for _, ct := range []string{"application/msgpack", "application/x-msgpack"} {
+		payload, _ := stubMsgPackCodec{}.Marshal(map[string]string{"name": "cosan"})
+		req := httptest.NewRequest("POST", "/echo", strings.NewReader(string(payload)))
+		req.Header.Set("Content-Type", ct)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var got map[string]string
+		if err := (stubMsgPackCodec{}).Unmarshal(w.Body.Bytes(), &got); err != nil || got["name"] != "cosan!" {
+			t.Errorf("%s: expected round-tripped 'cosan!', got %q (%v)", ct, w.Body.String(), err)
+		}
+		if got := w.Header().Get("Content-Type"); got != "application/msgpack" {
+			t.Errorf("%s: expected Content-Type application/msgpack, got %q", ct, got)
+		}
+	}
+}
+
+func TestMsgPackWithoutCodec(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader("x"))
+	req.Header.Set("Content-Type", "application/msgpack")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	if err := ctx.Bind(&map[string]string{}); !errors.Is(err, ErrNoMsgPackCodec) {
+		t.Errorf("Bind: expected ErrNoMsgPackCodec, got %v", err)
+	}
+	if err := ctx.MsgPack(200, "x"); !errors.Is(err, ErrNoMsgPackCodec) {
+		t.Errorf("MsgPack: expected ErrNoMsgPackCodec, got %v", err)
+	}
+}
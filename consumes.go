@@ -0,0 +1,142 @@
+package cosan
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Consumes restricts the route to requests whose Content-Type is one of
+// types, e.g. "application/json" or "image/*"; others receive 415
+// Unsupported Media Type. Parameters such as charset are ignored. The 415
+// runs through the global middleware with RoutePattern reporting
+// UnsupportedMediaTypeLabel.
+//
+// Several routes may share a method and pattern when their Consumes types
+// do not overlap, making the endpoint polymorphic. The route for a request
+// is chosen in this order:
+//
+//  1. a route consuming the exact media type,
+//  2. a route consuming a matching wildcard (type/* or */*), the first
+//     registered winning,
+//  3. the route registered without Consumes, if any.
+//
+// Every route sharing the pattern must be registered before the router is
+// compiled.
+//
+// Example:
+//
+//	router.POST("/upload", UploadJSON, cosan.Consumes("application/json"))
+//	router.POST("/upload", UploadFile, cosan.Consumes("multipart/form-data"))
+func Consumes(types ...string) RouteOption {
+	return func(r *route) {
+		for _, t := range types {
+			if mt := mediaType(t); mt != "" {
+				r.consumes = append(r.consumes, mt)
+			}
+		}
+	}
+}
+
+// unsupportedMediaTypeHandler rejects a request whose Content-Type no
+// Consumes route sharing the matched pattern accepts.
+func unsupportedMediaTypeHandler(ctx Context) error {
+	return NewHTTPError(http.StatusUnsupportedMediaType, http.StatusText(http.StatusUnsupportedMediaType))
+}
+
+// Produces declares the media types the route responds with, e.g.
+// "application/json". A request whose Accept header accepts none of them
+// receives 406 Not Acceptable before the handler runs; a request without
+// Accept is served. The types are listed in RouteInfo.Produces for
+// documentation generators.
+//
+// Example:
+//
+//	router.GET("/users/:id", GetUser, cosan.Produces("application/json", "application/msgpack"))
+func Produces(types ...string) RouteOption {
+	return func(r *route) {
+		for _, t := range types {
+			if mt := mediaType(t); mt != "" {
+				r.produces = append(r.produces, mt)
+			}
+		}
+	}
+}
+
+// producesHandler responds 406 unless the request accepts one of types.
+func producesHandler(next HandlerFunc, types []string) HandlerFunc {
+	return func(ctx Context) error {
+		accept := ctx.Request().Header.Get("Accept")
+		if accept != "" && negotiateMediaType(accept, types) == "" {
+			return NewHTTPError(http.StatusNotAcceptable, http.StatusText(http.StatusNotAcceptable))
+		}
+		return next(ctx)
+	}
+}
+
+// family returns r and the variants sharing its method and pattern, in
+// registration order.
+func (r *route) family() []*route {
+	if r.variants == nil {
+		return []*route{r}
+	}
+	return r.variants
+}
+
+// consumer returns the route, among r and its variants, that serves the
+// content type, or nil if none does.
+func (r *route) consumer(contentType string) *route {
+	mt := mediaType(contentType)
+
+	var wildcard, fallback *route
+	for _, rt := range r.family() {
+		if len(rt.consumes) == 0 {
+			if fallback == nil {
+				fallback = rt
+			}
+			continue
+		}
+		for _, c := range rt.consumes {
+			if c == mt {
+				return rt
+			}
+			if wildcard == nil && mt != "" && mediaRangeMatches(c, mt) {
+				wildcard = rt
+			}
+		}
+	}
+
+	if wildcard != nil {
+		return wildcard
+	}
+	return fallback
+}
+
+// mediaRangeMatches reports whether a wildcard media range (type/* or */*)
+// covers the media type.
+func mediaRangeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(mediaRange, "*")
+	return ok && strings.HasSuffix(prefix, "/") && strings.HasPrefix(mediaType, prefix)
+}
+
+// consumesConflict returns the route, among r and its variants, that would
+// serve the same requests as other, or nil if other can be added alongside
+// them. Two routes conflict when neither has Consumes or they share a type.
+func (r *route) consumesConflict(other *route) *route {
+	for _, rt := range r.family() {
+		if len(rt.consumes) == 0 && len(other.consumes) == 0 {
+			return rt
+		}
+		for _, c := range rt.consumes {
+			for _, o := range other.consumes {
+				if c == o {
+					return rt
+				}
+			}
+		}
+	}
+
+	return nil
+}
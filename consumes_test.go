@@ -0,0 +1,166 @@
+package cosan
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConsumes(t *testing.T) {
+	respond := func(name string) HandlerFunc {
+		return func(ctx Context) error { return ctx.String(200, "%s", name) }
+	}
+
+	r := New()
+	r.POST("/upload", respond("json"), Consumes("application/json"), WithName("upload.json"))
+	r.POST("/upload", respond("multipart"), Consumes("multipart/form-data"))
+	r.POST("/upload", respond("image"), Consumes("image/*"))
+	r.POST("/upload", respond("png"), Consumes("image/png"))
+	r.POST("/strict", respond("strict"), Consumes("application/json"))
+	r.POST("/mixed", respond("json"), Consumes("application/json"))
+	r.POST("/mixed", respond("any"))
+
+	tests := []struct {
+		path        string
+		contentType string
+		code        int
+		body        string
+	}{
+		{"/upload", "application/json", 200, "json"},
+		{"/upload", "application/json; charset=utf-8", 200, "json"},
+		{"/upload", "multipart/form-data; boundary=x", 200, "multipart"},
+		{"/upload", "image/png", 200, "png"},
+		{"/upload", "image/jpeg", 200, "image"},
+		{"/upload", "text/plain", 415, ""},
+		{"/upload", "", 415, ""},
+		{"/strict", "application/json", 200, "strict"},
+		{"/strict", "application/xml", 415, ""},
+		{"/mixed", "application/json", 200, "json"},
+		{"/mixed", "text/plain", 200, "any"},
+		{"/mixed", "", 200, "any"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, strings.NewReader("{}"))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.code || (tt.body != "" && w.Body.String() != tt.body) {
+			t.Errorf("%s %q: expected %d %q, got %d %q", tt.path, tt.contentType, tt.code, tt.body, w.Code, w.Body.String())
+		}
+	}
+
+	var consumes [][]string
+	for _, info := range r.GetRoutes() {
+		if info.Pattern == "/upload" {
+			consumes = append(consumes, info.Consumes)
+		}
+	}
+	want := [][]string{{"application/json"}, {"multipart/form-data"}, {"image/*"}, {"image/png"}}
+	if !reflect.DeepEqual(consumes, want) {
+		t.Errorf("Expected GetRoutes to list every /upload route, got %v", consumes)
+	}
+	if info := r.FindRoute("upload.json"); info == nil || info.Pattern != "/upload" {
+		t.Errorf("Expected FindRoute to find the first /upload route, got %+v", info)
+	}
+}
+
+func TestConsumes_UnsupportedMediaTypeMiddleware(t *testing.T) {
+	var pattern string
+	r := New()
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.Header().Set("X-Request-ID", "abc")
+			return next(ctx)
+		}
+	}))
+	r.SetErrorHandler(func(ctx Context, err error) {
+		pattern = ctx.RoutePattern()
+		_ = ctx.String(ErrorStatus(err), "%v", err)
+	})
+	r.POST("/upload", func(ctx Context) error {
+		return ctx.String(200, "json")
+	}, Consumes("application/json"))
+
+	req := httptest.NewRequest("POST", "/upload", strings.NewReader("x"))
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 415 {
+		t.Errorf("Expected 415, got %d", w.Code)
+	}
+	if w.Header().Get("X-Request-ID") != "abc" {
+		t.Error("Expected the global middleware to run for the 415")
+	}
+	if pattern != UnsupportedMediaTypeLabel {
+		t.Errorf("Expected RoutePattern %q in the error handler, got %q", UnsupportedMediaTypeLabel, pattern)
+	}
+}
+
+func TestConsumes_Conflicts(t *testing.T) {
+	handler := func(ctx Context) error { return nil }
+
+	tests := []struct {
+		name   string
+		first  []RouteOption
+		second []RouteOption
+	}{
+		{"same type", []RouteOption{Consumes("application/json")}, []RouteOption{Consumes("text/plain", "application/json")}},
+		{"both unconstrained", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			r.POST("/upload", handler, tt.first...)
+
+			defer func() {
+				if rec := recover(); rec == nil || !strings.Contains(rec.(string), "duplicate route registration") {
+					t.Errorf("Expected a duplicate route panic, got %v", rec)
+				}
+			}()
+			r.POST("/upload", handler, tt.second...)
+		})
+	}
+}
+
+func TestProduces(t *testing.T) {
+	r := New()
+	r.GET("/users/:id", func(ctx Context) error {
+		return ctx.JSON(200, map[string]string{"id": ctx.Param("id")})
+	}, Produces("application/json"))
+
+	tests := []struct {
+		accept string
+		code   int
+	}{
+		{"", 200},
+		{"application/json", 200},
+		{"text/html, application/*;q=0.5", 200},
+		{"*/*", 200},
+		{"text/html", 406},
+		{"application/json;q=0", 406},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/users/7", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("Accept %q: expected %d, got %d", tt.accept, tt.code, w.Code)
+		}
+	}
+
+	if routes := r.GetRoutes(); !reflect.DeepEqual(routes[0].Produces, []string{"application/json"}) {
+		t.Errorf("Expected RouteInfo.Produces [application/json], got %v", routes[0].Produces)
+	}
+}
@@ -1,10 +1,17 @@
 package cosan
 
 import (
-	"encoding/json"
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 )
 
 // context is the default implementation of the Context interface.
@@ -13,6 +20,19 @@ type context struct {
 	res    http.ResponseWriter
 	params map[string]string
 	values map[string]interface{}
+	router *router // Serving router, for router-level settings; nil outside ServeHTTP
+
+	recorder   *statusRecorder // Status capture installed by ServeHTTP
+	route      *route          // Matched route, set when its handler chain starts
+	logger     *slog.Logger    // Request-scoped logger, derived on first use
+	query      url.Values      // Parsed query string, cached on first use
+	body       []byte          // Request body, cached by BodyBytes
+	bodyRead   bool
+	formErr    error // Error from parsing the form, cached by parseForm
+	formParsed bool
+	allowed    []string       // Methods for the Allow header of a 405
+	timedOut   *timeoutWriter // Writer of a handler that outlived its timeout
+	pool       *sync.Pool     // Pool the context returns to on release; nil if unpooled
 }
 
 // newContext creates a new context for a request.
@@ -35,6 +55,50 @@ func (c *context) Response() http.ResponseWriter {
 	return c.res
 }
 
+// SetResponse replaces the underlying http.ResponseWriter.
+func (c *context) SetResponse(w http.ResponseWriter) {
+	c.res = w
+}
+
+// RoutePattern returns the pattern of the matched route, or "".
+func (c *context) RoutePattern() string {
+	if c.route == nil {
+		return ""
+	}
+	return c.route.pattern
+}
+
+// RouteName returns the WithName name of the matched route, or "".
+func (c *context) RouteName() string {
+	if c.route == nil || c.route.metadata == nil {
+		return ""
+	}
+	return c.route.metadata.Name
+}
+
+// RouteTags returns the WithTags tags of the matched route, or nil.
+func (c *context) RouteTags() []string {
+	if c.route == nil || c.route.metadata == nil {
+		return nil
+	}
+	return c.route.metadata.Tags
+}
+
+// WithRequest replaces the request and drops state cached from the old one.
+func (c *context) WithRequest(req *http.Request) {
+	c.req = req
+	c.query = nil
+	c.body = nil
+	c.bodyRead = false
+	c.logger = nil
+}
+
+// committed reports whether the response status has already been sent or the
+// connection was hijacked.
+func (c *context) committed() bool {
+	return c.recorder != nil && (c.recorder.written || c.recorder.hijacked)
+}
+
 // Param returns the value of the named path parameter.
 func (c *context) Param(key string) string {
 	return c.params[key]
@@ -47,17 +111,139 @@ func (c *context) Params() map[string]string {
 
 // Query returns the first value of the named query parameter.
 func (c *context) Query(key string) string {
-	return c.req.URL.Query().Get(key)
+	return c.queryValues().Get(key)
+}
+
+// queryValues parses the query string once per request.
+func (c *context) queryValues() url.Values {
+	if c.query == nil {
+		c.query = c.req.URL.Query()
+	}
+	return c.query
+}
+
+// QueryString returns the raw, still-encoded query string, without the "?".
+func (c *context) QueryString() string {
+	return c.req.URL.RawQuery
+}
+
+// QueryValues returns the parsed query parameters, cached for the request.
+func (c *context) QueryValues() url.Values {
+	return c.queryValues()
 }
 
 // QueryAll returns all values of the named query parameter.
 func (c *context) QueryAll(key string) []string {
-	return c.req.URL.Query()[key]
+	return c.queryValues()[key]
+}
+
+// QueryDefault returns the named query parameter, or def if missing or empty.
+func (c *context) QueryDefault(key, def string) string {
+	if v := c.Query(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// QueryInt returns the named query parameter parsed as an int.
+func (c *context) QueryInt(key string) (int, error) {
+	v := c.Query(key)
+	if v == "" {
+		return 0, fmt.Errorf("query parameter %q is missing", key)
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("query parameter %q: %w", key, err)
+	}
+
+	return n, nil
+}
+
+// QueryIntDefault returns the named query parameter as an int, or def.
+func (c *context) QueryIntDefault(key string, def int) int {
+	n, err := c.QueryInt(key)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// QueryBool returns the named query parameter parsed as a bool.
+func (c *context) QueryBool(key string) bool {
+	b, err := strconv.ParseBool(c.Query(key))
+	return err == nil && b
+}
+
+// BindQuery maps query parameters into struct fields tagged `query`.
+func (c *context) BindQuery(v interface{}) error {
+	query := c.queryValues()
+	return bindTagged(v, "query", func(key string) ([]string, bool) {
+		values, ok := query[key]
+		return values, ok
+	})
+}
+
+// BindHeader maps request headers into struct fields tagged `header`.
+func (c *context) BindHeader(v interface{}) error {
+	return bindTagged(v, "header", func(key string) ([]string, bool) {
+		values, ok := c.req.Header[http.CanonicalHeaderKey(key)]
+		return values, ok
+	})
+}
+
+// BindParams maps path parameters into struct fields tagged `param`.
+func (c *context) BindParams(v interface{}) error {
+	return bindTagged(v, "param", func(key string) ([]string, bool) {
+		value, ok := c.params[key]
+		return []string{value}, ok
+	})
+}
+
+// BindAll populates a struct from body, headers, query, and path parameters,
+// in increasing order of precedence.
+func (c *context) BindAll(v interface{}) error {
+	if err := c.Bind(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if err := c.BindHeader(v); err != nil {
+		return err
+	}
+	if err := c.BindQuery(v); err != nil {
+		return err
+	}
+
+	return c.BindParams(v)
+}
+
+// Validate checks v using the router's Validator.
+func (c *context) Validate(v interface{}) error {
+	if c.router == nil || c.router.validator == nil {
+		return ErrNoValidator
+	}
+	return c.router.validator.Validate(v)
 }
 
 // Bind parses the request body into the provided struct.
 // For Phase 1, this only supports JSON.
 func (c *context) Bind(v interface{}) error {
+	switch mediaType(c.req.Header.Get("Content-Type")) {
+	case protobufContentType, "application/protobuf":
+		return c.bindProto(v)
+	case msgPackContentType, "application/x-msgpack":
+		return c.bindMsgPack(v)
+	}
+	return c.bindJSON(v, c.router != nil && c.router.strictJSON)
+}
+
+// BindStrict parses the JSON request body like Bind, but rejects fields
+// that are not present in the target struct.
+func (c *context) BindStrict(v interface{}) error {
+	return c.bindJSON(v, true)
+}
+
+// bindJSON decodes the JSON request body, optionally rejecting unknown fields.
+func (c *context) bindJSON(v interface{}, strict bool) error {
 	contentType := c.req.Header.Get("Content-Type")
 
 	// For Phase 1, only support JSON
@@ -65,7 +251,19 @@ func (c *context) Bind(v interface{}) error {
 		return fmt.Errorf("unsupported content type: %s", contentType)
 	}
 
-	decoder := json.NewDecoder(c.req.Body)
+	body, err := c.bindBody()
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	decoder := c.jsonCodec().NewDecoder(bytes.NewReader(body))
+	if strict {
+		d, ok := decoder.(interface{ DisallowUnknownFields() })
+		if !ok {
+			return errStrictUnsupported
+		}
+		d.DisallowUnknownFields()
+	}
 	if err := decoder.Decode(v); err != nil {
 		return fmt.Errorf("failed to decode JSON: %w", err)
 	}
@@ -74,39 +272,95 @@ func (c *context) Bind(v interface{}) error {
 }
 
 // BodyBytes returns the raw request body as bytes.
+// The body is read once and cached, and Request().Body is replaced with a
+// reader over the cached bytes, so BodyBytes, Bind, and handlers reading
+// the body directly can be combined in any order.
 func (c *context) BodyBytes() ([]byte, error) {
-	return io.ReadAll(c.req.Body)
+	if c.bodyRead {
+		return c.body, nil
+	}
+
+	body, err := io.ReadAll(c.req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.body = body
+	c.bodyRead = true
+	c.req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
 }
 
 // JSON writes a JSON response with the given status code.
+// The value is encoded to a buffer first, so an encoding failure returns an
+// error before any status or body bytes are committed.
 func (c *context) JSON(code int, v interface{}) error {
-	c.res.Header().Set("Content-Type", "application/json")
-	c.res.WriteHeader(code)
+	return c.writeJSON(code, "application/json", v)
+}
+
+// OK writes v as a 200 JSON response.
+func (c *context) OK(v interface{}) error {
+	return c.JSON(http.StatusOK, v)
+}
 
-	encoder := json.NewEncoder(c.res)
-	if err := encoder.Encode(v); err != nil {
+// writeJSON encodes v before touching the response, so an encoding failure
+// leaves the status and headers unwritten.
+func (c *context) writeJSON(code int, contentType string, v interface{}) error {
+	body, err := c.jsonCodec().Marshal(v)
+	if err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
+	body = append(body, '\n')
 
-	return nil
+	c.res.Header().Set("Content-Type", contentType)
+	c.res.WriteHeader(code)
+	_, err = c.res.Write(body)
+	return err
 }
 
 // String writes a formatted string response with the given status code.
 func (c *context) String(code int, format string, args ...interface{}) error {
-	c.res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	c.res.Header().Set("Content-Type", c.textContentType("text/plain"))
 	c.res.WriteHeader(code)
 	_, err := fmt.Fprintf(c.res, format, args...)
 	return err
 }
 
+// Text writes a formatted plain-text response with status 200.
+func (c *context) Text(format string, args ...interface{}) error {
+	return c.String(http.StatusOK, format, args...)
+}
+
+// textContentType appends the router's default charset to a text media type.
+func (c *context) textContentType(mediaType string) string {
+	charset := DefaultCharset
+	if c.router != nil {
+		charset = c.router.charset
+	}
+	if charset == "" {
+		return mediaType
+	}
+	return mediaType + "; charset=" + charset
+}
+
 // HTML writes an HTML response with the given status code.
 func (c *context) HTML(code int, html string) error {
-	c.res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	c.res.Header().Set("Content-Type", c.textContentType("text/html"))
 	c.res.WriteHeader(code)
 	_, err := c.res.Write([]byte(html))
 	return err
 }
 
+// Blob writes raw bytes with the given status code and content type.
+func (c *context) Blob(code int, contentType string, data []byte) error {
+	header := c.res.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.Itoa(len(data)))
+	c.res.WriteHeader(code)
+	_, err := c.res.Write(data)
+	return err
+}
+
 // Status sets the HTTP status code.
 func (c *context) Status(code int) {
 	c.res.WriteHeader(code)
@@ -122,6 +376,46 @@ func (c *context) Write(b []byte) (int, error) {
 	return c.res.Write(b)
 }
 
+// IsCanceled reports whether the request's context is done, e.g. because
+// the client disconnected or a deadline passed.
+func (c *context) IsCanceled() bool {
+	return c.req.Context().Err() != nil
+}
+
+// Err returns the request context's error: nil while the request is live,
+// context.Canceled or context.DeadlineExceeded once it is done.
+func (c *context) Err() error {
+	return c.req.Context().Err()
+}
+
+// Flush sends any buffered response data to the client.
+// It returns http.ErrNotSupported when no writer in the chain can flush.
+func (c *context) Flush() error {
+	return http.NewResponseController(c.res).Flush()
+}
+
+// Hijack takes over the underlying connection, e.g. for a WebSocket upgrade.
+// It returns http.ErrNotSupported when the connection cannot be hijacked.
+func (c *context) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(c.res).Hijack()
+}
+
+// BytesWritten returns the number of response body bytes written so far.
+func (c *context) BytesWritten() int64 {
+	if c.recorder == nil {
+		return 0
+	}
+	return c.recorder.bytes
+}
+
+// StatusCode returns the response status written so far, or 200.
+func (c *context) StatusCode() int {
+	if c.recorder == nil {
+		return http.StatusOK
+	}
+	return c.recorder.statusCode
+}
+
 // Set stores a value in the context for the request lifetime.
 func (c *context) Set(key string, value interface{}) {
 	c.values[key] = value
@@ -131,3 +425,29 @@ func (c *context) Set(key string, value interface{}) {
 func (c *context) Get(key string) interface{} {
 	return c.values[key]
 }
+
+// MustGet retrieves a value from the context, panicking if key is absent.
+func (c *context) MustGet(key string) interface{} {
+	v, ok := c.values[key]
+	if !ok {
+		panic(fmt.Sprintf("cosan: key %q not found in context", key))
+	}
+	return v
+}
+
+// GetValue returns the context value stored under key as a T.
+// It reports false if the key is missing or holds a value of another type,
+// instead of panicking like a failed type assertion.
+//
+// Example:
+//
+//	claims, ok := cosan.GetValue[*Claims](ctx, "claims")
+func GetValue[T any](ctx Context, key string) (T, bool) {
+	v, ok := ctx.Get(key).(T)
+	return v, ok
+}
+
+// SetValue stores a typed value in the context, for retrieval with GetValue.
+func SetValue[T any](ctx Context, key string, val T) {
+	ctx.Set(key, val)
+}
@@ -0,0 +1,445 @@
+package cosan
+
+import (
+	stdcontext "context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_QueryHelpers(t *testing.T) {
+	req := httptest.NewRequest("GET", "/search?page=3&limit=abc&empty=&verbose=true&debug=0", nil)
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	if got := ctx.QueryDefault("page", "1"); got != "3" {
+		t.Errorf("QueryDefault(page) = %q, want 3", got)
+	}
+	if got := ctx.QueryDefault("empty", "x"); got != "x" {
+		t.Errorf("QueryDefault(empty) = %q, want default", got)
+	}
+	if got := ctx.QueryDefault("missing", "x"); got != "x" {
+		t.Errorf("QueryDefault(missing) = %q, want default", got)
+	}
+
+	if n, err := ctx.QueryInt("page"); err != nil || n != 3 {
+		t.Errorf("QueryInt(page) = %d, %v; want 3, nil", n, err)
+	}
+	if _, err := ctx.QueryInt("limit"); err == nil {
+		t.Error("QueryInt(limit) should fail for non-integer value")
+	}
+	if _, err := ctx.QueryInt("missing"); err == nil {
+		t.Error("QueryInt(missing) should fail for missing key")
+	}
+
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"page", 3},
+		{"limit", 10},
+		{"empty", 10},
+		{"missing", 10},
+	}
+	for _, tt := range tests {
+		if got := ctx.QueryIntDefault(tt.key, 10); got != tt.want {
+			t.Errorf("QueryIntDefault(%s) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+
+	if !ctx.QueryBool("verbose") {
+		t.Error("QueryBool(verbose) should be true")
+	}
+	if ctx.QueryBool("debug") || ctx.QueryBool("missing") || ctx.QueryBool("limit") {
+		t.Error("QueryBool should be false for 0, missing, and invalid values")
+	}
+}
+
+func TestContext_QueryStringAndValues(t *testing.T) {
+	req := httptest.NewRequest("GET", "/sign?b=2&a=1&a=x%20y", nil)
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	if got := ctx.QueryString(); got != "b=2&a=1&a=x%20y" {
+		t.Errorf("QueryString() = %q, want raw query", got)
+	}
+
+	values := ctx.QueryValues()
+	if got := values["a"]; len(got) != 2 || got[1] != "x y" {
+		t.Errorf("QueryValues()[a] = %q, want [1 x y]", got)
+	}
+	if values.Get("b") != "2" || ctx.Query("b") != "2" {
+		t.Errorf("QueryValues() = %v, want b=2", values)
+	}
+}
+
+func TestContext_BindStrict(t *testing.T) {
+	type User struct {
+		Username string `json:"username"`
+	}
+
+	body := `{"usernmae": "ada"}`
+
+	// Default Bind silently drops unknown fields
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)), nil)
+	var u User
+	if err := ctx.Bind(&u); err != nil {
+		t.Fatalf("Bind should ignore unknown fields, got %v", err)
+	}
+
+	ctx = newContext(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)), nil)
+	err := ctx.BindStrict(&u)
+	if err == nil || !strings.Contains(err.Error(), `"usernmae"`) {
+		t.Errorf("Expected error naming the unknown field, got %v", err)
+	}
+}
+
+func TestRouter_WithStrictJSON(t *testing.T) {
+	router := New(WithStrictJSON(true))
+	router.POST("/users", func(ctx Context) error {
+		var u struct {
+			Username string `json:"username"`
+		}
+		if err := ctx.Bind(&u); err != nil {
+			return ctx.String(400, err.Error())
+		}
+		return ctx.String(200, u.Username)
+	})
+
+	tests := []struct {
+		body     string
+		wantCode int
+	}{
+		{`{"username": "ada"}`, 200},
+		{`{"usernmae": "ada"}`, 400},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/users", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("Body %s: expected %d, got %d (%s)", tt.body, tt.wantCode, w.Code, w.Body.String())
+		}
+	}
+}
+
+type minLengthValidator struct{}
+
+func (minLengthValidator) Validate(v interface{}) error {
+	if s, ok := v.(*string); ok && len(*s) < 3 {
+		return errors.New("too short")
+	}
+	return nil
+}
+
+func TestContext_Validate(t *testing.T) {
+	router := New(WithValidator(minLengthValidator{}))
+	router.GET("/validate/:name", func(ctx Context) error {
+		name := ctx.Param("name")
+		if err := ctx.Validate(&name); err != nil {
+			return ctx.String(400, err.Error())
+		}
+		return ctx.String(200, name)
+	})
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/validate/ada", 200, "ada"},
+		{"/validate/al", 400, "too short"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if w.Code != tt.wantCode || w.Body.String() != tt.wantBody {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.path, tt.wantCode, tt.wantBody, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestContext_ValidateWithoutValidator(t *testing.T) {
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	if err := ctx.Validate(struct{}{}); !errors.Is(err, ErrNoValidator) {
+		t.Errorf("Expected ErrNoValidator, got %v", err)
+	}
+}
+
+func TestContext_JSONEncodeFailureWritesNothing(t *testing.T) {
+	router := New()
+	router.GET("/bad", func(ctx Context) error {
+		return ctx.JSON(200, map[string]interface{}{"ch": make(chan int)})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/bad", nil))
+
+	if w.Code != 500 {
+		t.Errorf("Expected clean 500 from error handler, got %d", w.Code)
+	}
+	if strings.HasPrefix(w.Body.String(), "{") {
+		t.Errorf("Expected no partial JSON in body, got %q", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Expected error content type, got %q", ct)
+	}
+}
+
+func TestContext_BytesWritten(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler HandlerFunc
+	}{
+		{"json", func(ctx Context) error { return ctx.JSON(200, map[string]string{"hello": "world"}) }},
+		{"string", func(ctx Context) error { return ctx.String(200, "hello %s", "world") }},
+		{"html", func(ctx Context) error { return ctx.HTML(200, "<p>hello</p>") }},
+		{"write", func(ctx Context) error {
+			ctx.Write([]byte("chunk one,"))
+			_, err := ctx.Write([]byte("chunk two"))
+			return err
+		}},
+		{"not modified", func(ctx Context) error {
+			ctx.Status(304)
+			return nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written int64
+			r := New()
+			r.GET("/", func(ctx Context) error {
+				err := tt.handler(ctx)
+				written = ctx.BytesWritten()
+				return err
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+			if written != int64(w.Body.Len()) {
+				t.Errorf("BytesWritten() = %d, body length %d", written, w.Body.Len())
+			}
+		})
+	}
+}
+
+func TestContext_StatusCode(t *testing.T) {
+	var before, after int
+	r := New()
+	r.GET("/", func(ctx Context) error {
+		before = ctx.StatusCode()
+		err := ctx.String(201, "created")
+		after = ctx.StatusCode()
+		return err
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if before != 200 || after != 201 {
+		t.Errorf("Expected StatusCode 200 before and 201 after writing, got %d and %d", before, after)
+	}
+}
+
+func TestContext_BodyBytesThenBind(t *testing.T) {
+	r := New()
+	var seen []byte
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			body, err := ctx.BodyBytes()
+			if err != nil {
+				return err
+			}
+			seen = body
+			return next(ctx)
+		}
+	}))
+	r.POST("/users", func(ctx Context) error {
+		var user struct{ Name string }
+		if err := ctx.Bind(&user); err != nil {
+			return err
+		}
+		again, err := ctx.BodyBytes()
+		if err != nil {
+			return err
+		}
+		return ctx.String(200, "%s %d", user.Name, len(again))
+	})
+
+	body := `{"Name":"ada"}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users", strings.NewReader(body)))
+
+	if w.Code != 200 || w.Body.String() != "ada 14" {
+		t.Errorf("Expected 200 'ada 14', got %d %q", w.Code, w.Body.String())
+	}
+	if string(seen) != body {
+		t.Errorf("Middleware saw %q", seen)
+	}
+}
+
+func TestGetValue(t *testing.T) {
+	type claims struct{ Subject string }
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	SetValue(ctx, "claims", &claims{Subject: "ada"})
+	SetValue(ctx, "count", 3)
+
+	if c, ok := GetValue[*claims](ctx, "claims"); !ok || c.Subject != "ada" {
+		t.Errorf("GetValue[*claims] = %v, %v", c, ok)
+	}
+	if n, ok := GetValue[int](ctx, "count"); !ok || n != 3 {
+		t.Errorf("GetValue[int] = %d, %v", n, ok)
+	}
+	if s, ok := GetValue[string](ctx, "count"); ok || s != "" {
+		t.Errorf("Expected type mismatch to report false, got %q, %v", s, ok)
+	}
+	if _, ok := GetValue[int](ctx, "missing"); ok {
+		t.Error("Expected missing key to report false")
+	}
+}
+
+func TestContext_MustGet(t *testing.T) {
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	ctx.Set("claims", "ada")
+
+	if got := ctx.MustGet("claims"); got != "ada" {
+		t.Errorf("MustGet(claims) = %v, want ada", got)
+	}
+
+	defer func() {
+		rec := recover()
+		if rec != `cosan: key "missing" not found in context` {
+			t.Errorf("Unexpected panic value %v", rec)
+		}
+	}()
+	ctx.MustGet("missing")
+	t.Error("Expected MustGet to panic for a missing key")
+}
+
+func TestContext_IsCanceled(t *testing.T) {
+	reqCtx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	req := httptest.NewRequest("GET", "/", nil).WithContext(reqCtx)
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	if ctx.IsCanceled() || ctx.Err() != nil {
+		t.Fatalf("Expected live request, got canceled=%v err=%v", ctx.IsCanceled(), ctx.Err())
+	}
+
+	cancel()
+
+	if !ctx.IsCanceled() {
+		t.Error("Expected IsCanceled after the client disconnects")
+	}
+	if !errors.Is(ctx.Err(), stdcontext.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", ctx.Err())
+	}
+}
+
+func TestRouter_WithDefaultCharset(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantText string
+		wantHTML string
+	}{
+		{"default", nil, "text/plain; charset=utf-8", "text/html; charset=utf-8"},
+		{"latin1", []Option{WithDefaultCharset("iso-8859-1")}, "text/plain; charset=iso-8859-1", "text/html; charset=iso-8859-1"},
+		{"omitted", []Option{WithDefaultCharset("")}, "text/plain", "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.opts...)
+			r.GET("/text", func(ctx Context) error { return ctx.String(200, "hi") })
+			r.GET("/html", func(ctx Context) error { return ctx.HTML(200, "<p>hi</p>") })
+			r.GET("/json", func(ctx Context) error { return ctx.JSON(200, "hi") })
+
+			for path, want := range map[string]string{"/text": tt.wantText, "/html": tt.wantHTML, "/json": "application/json"} {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+				if got := w.Header().Get("Content-Type"); got != want {
+					t.Errorf("%s: expected Content-Type %q, got %q", path, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestContext_Blob(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "/logo.png", nil), nil)
+	data := []byte{0x89, 'P', 'N', 'G'}
+
+	if err := ctx.Blob(200, "image/png", data); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != 200 {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Expected Content-Type image/png, got %q", ct)
+	}
+	if cl := w.Header().Get("Content-Length"); cl != "4" {
+		t.Errorf("Expected Content-Length 4, got %q", cl)
+	}
+	if w.Body.String() != string(data) {
+		t.Errorf("Unexpected body %q", w.Body.Bytes())
+	}
+}
+
+func TestContext_TextAndOK(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "/", nil), nil)
+	if err := ctx.Text("hello %s", "world"); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != 200 || w.Body.String() != "hello world" || w.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("Text: got %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+
+	w = httptest.NewRecorder()
+	ctx = newContext(w, httptest.NewRequest("GET", "/", nil), nil)
+	if err := ctx.OK(map[string]int{"count": 3}); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != 200 || w.Body.String() != "{\"count\":3}\n" || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("OK: got %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+}
+
+type ctxKey struct{}
+
+func TestContext_WithRequest(t *testing.T) {
+	r := New()
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			_ = ctx.Query("mode") // populate the query cache
+			req := ctx.Request()
+			override := req.Clone(stdcontext.WithValue(req.Context(), ctxKey{}, "injected"))
+			override.Method = req.Header.Get("X-HTTP-Method-Override")
+			override.URL.RawQuery = "mode=override"
+			ctx.WithRequest(override)
+			return next(ctx)
+		}
+	}))
+
+	var method, value, mode string
+	r.POST("/items", func(ctx Context) error {
+		method = ctx.Request().Method
+		value, _ = ctx.Request().Context().Value(ctxKey{}).(string)
+		mode = ctx.Query("mode")
+		return nil
+	})
+
+	req := httptest.NewRequest("POST", "/items?mode=original", nil)
+	req.Header.Set("X-HTTP-Method-Override", "PATCH")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if method != "PATCH" || value != "injected" || mode != "override" {
+		t.Errorf("Expected downstream to see the new request, got method=%q value=%q mode=%q", method, value, mode)
+	}
+}
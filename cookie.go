@@ -0,0 +1,146 @@
+package cosan
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// signedCookieVersion prefixes signed cookie values so the format can evolve
+// without misreading cookies issued by an older scheme.
+const signedCookieVersion = "v1"
+
+// defaultCookie is the baseline SetCookie applies without WithCookieDefaults.
+var defaultCookie = http.Cookie{HttpOnly: true, SameSite: http.SameSiteLaxMode}
+
+// WithCookieDefaults sets the baseline SetCookie applies to every cookie,
+// replacing the default of HttpOnly and SameSite=Lax. Path, Domain, and
+// SameSite fill in cookies that leave them unset; HttpOnly and Secure are
+// added when the baseline sets them. Cookies on requests over TLS are
+// always marked Secure.
+//
+// Because a cookie's false HttpOnly cannot be told apart from an unset one,
+// a cookie that scripts must read (e.g. a CSRF token) is set with
+// SetCookieRaw, which bypasses the baseline.
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithCookieDefaults(http.Cookie{
+//		Path:     "/",
+//		HttpOnly: true,
+//		Secure:   true, // TLS terminated by a proxy
+//		SameSite: http.SameSiteStrictMode,
+//	}))
+func WithCookieDefaults(defaults http.Cookie) Option {
+	return func(r *router) {
+		r.cookieDefaults = &defaults
+	}
+}
+
+// cookieDefaults returns the router's cookie baseline.
+func (c *context) cookieDefaults() *http.Cookie {
+	if c.router == nil || c.router.cookieDefaults == nil {
+		return &defaultCookie
+	}
+	return c.router.cookieDefaults
+}
+
+// Cookie returns the named request cookie, or http.ErrNoCookie.
+func (c *context) Cookie(name string) (*http.Cookie, error) {
+	return c.req.Cookie(name)
+}
+
+// SetCookie adds a Set-Cookie header to the response, completing cookie
+// with the defaults described at WithCookieDefaults. cookie itself is not
+// modified.
+func (c *context) SetCookie(cookie *http.Cookie) {
+	defaults := c.cookieDefaults()
+
+	set := *cookie
+	if set.Path == "" {
+		set.Path = defaults.Path
+	}
+	if set.Domain == "" {
+		set.Domain = defaults.Domain
+	}
+	if set.SameSite == 0 {
+		set.SameSite = defaults.SameSite
+	}
+	set.HttpOnly = set.HttpOnly || defaults.HttpOnly
+	set.Secure = set.Secure || defaults.Secure || c.req.TLS != nil
+
+	http.SetCookie(c.res, &set)
+}
+
+// SetCookieRaw adds a Set-Cookie header for cookie exactly as given,
+// without the defaults SetCookie applies. Use it for the cookies that must
+// differ from the baseline where SetCookie cannot tell, e.g. a CSRF token
+// that scripts read and so must not be HttpOnly.
+//
+// Example:
+//
+//	ctx.SetCookieRaw(&http.Cookie{Name: "csrf", Value: token, Path: "/", Secure: true, SameSite: http.SameSiteStrictMode})
+func (c *context) SetCookieRaw(cookie *http.Cookie) {
+	http.SetCookie(c.res, cookie)
+}
+
+// SetSignedCookie sets a cookie whose value is signed with HMAC-SHA256, so
+// that SignedCookie can detect tampering. The value is not encrypted.
+//
+// The cookie value has the form "v1.<base64 value>.<base64 signature>";
+// the signature also covers the cookie name, so a signed value cannot be
+// replayed under another cookie.
+func (c *context) SetSignedCookie(name, value string, secret []byte) {
+	encoded := signedCookieVersion + "." + base64.RawURLEncoding.EncodeToString([]byte(value))
+
+	c.SetCookie(&http.Cookie{
+		Name:     name,
+		Value:    encoded + "." + base64.RawURLEncoding.EncodeToString(signCookie(name, encoded, secret)),
+		Path:     "/",
+		HttpOnly: true,
+	})
+}
+
+// SignedCookie returns the value of a cookie set with SetSignedCookie.
+// It returns http.ErrNoCookie if the cookie is missing and
+// ErrInvalidCookieSignature if it was tampered with or signed with another secret.
+func (c *context) SignedCookie(name string, secret []byte) (string, error) {
+	cookie, err := c.req.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	i := strings.LastIndexByte(cookie.Value, '.')
+	if i < 0 {
+		return "", ErrInvalidCookieSignature
+	}
+	encoded, sig := cookie.Value[:i], cookie.Value[i+1:]
+
+	version, payload, ok := strings.Cut(encoded, ".")
+	if !ok || version != signedCookieVersion {
+		return "", ErrInvalidCookieSignature
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, signCookie(name, encoded, secret)) {
+		return "", ErrInvalidCookieSignature
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrInvalidCookieSignature
+	}
+
+	return string(value), nil
+}
+
+// signCookie computes the HMAC-SHA256 of the cookie name and encoded value.
+func signCookie(name, encoded string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(name))
+	mac.Write([]byte{'='})
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,145 @@
+package cosan
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContext_SignedCookie(t *testing.T) {
+	secret := []byte("s3cret")
+
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "/", nil), nil)
+	ctx.SetSignedCookie("session", "user=42", secret)
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got %d", len(cookies))
+	}
+	issued := cookies[0]
+	if !strings.HasPrefix(issued.Value, "v1.") {
+		t.Errorf("Expected versioned cookie value, got %q", issued.Value)
+	}
+
+	read := func(c *http.Cookie, secret []byte) (string, error) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(c)
+		return newContext(httptest.NewRecorder(), req, nil).SignedCookie(c.Name, secret)
+	}
+
+	if value, err := read(issued, secret); err != nil || value != "user=42" {
+		t.Errorf("SignedCookie() = %q, %v; want user=42, nil", value, err)
+	}
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+		secret []byte
+	}{
+		{"wrong secret", issued, []byte("other")},
+		{"tampered value", &http.Cookie{Name: "session", Value: strings.Replace(issued.Value, "v1.d", "v1.e", 1)}, secret},
+		{"renamed cookie", &http.Cookie{Name: "admin", Value: issued.Value}, secret},
+		{"unknown version", &http.Cookie{Name: "session", Value: "v0" + issued.Value[2:]}, secret},
+		{"unsigned", &http.Cookie{Name: "session", Value: "user=42"}, secret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := read(tt.cookie, tt.secret); !errors.Is(err, ErrInvalidCookieSignature) {
+				t.Errorf("Expected ErrInvalidCookieSignature, got %v", err)
+			}
+		})
+	}
+
+	missing := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	if _, err := missing.SignedCookie("session", secret); !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("Expected http.ErrNoCookie, got %v", err)
+	}
+}
+
+func TestContext_SetCookieDefaults(t *testing.T) {
+	tlsRequest := httptest.NewRequest("GET", "https://example.com/", nil)
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		req    *http.Request
+		cookie *http.Cookie
+		want   string
+	}{
+		{
+			name:   "built-in defaults",
+			cookie: &http.Cookie{Name: "a", Value: "1"},
+			want:   "a=1; HttpOnly; SameSite=Lax",
+		},
+		{
+			name:   "secure over TLS",
+			req:    tlsRequest,
+			cookie: &http.Cookie{Name: "a", Value: "1"},
+			want:   "a=1; HttpOnly; Secure; SameSite=Lax",
+		},
+		{
+			name:   "cookie overrides SameSite",
+			cookie: &http.Cookie{Name: "a", Value: "1", Path: "/app", SameSite: http.SameSiteStrictMode},
+			want:   "a=1; Path=/app; HttpOnly; SameSite=Strict",
+		},
+		{
+			name: "custom baseline",
+			opts: []Option{WithCookieDefaults(http.Cookie{
+				Path:     "/",
+				Secure:   true,
+				SameSite: http.SameSiteNoneMode,
+			})},
+			cookie: &http.Cookie{Name: "csrf", Value: "t"},
+			want:   "csrf=t; Path=/; Secure; SameSite=None",
+		},
+		{
+			name:   "custom baseline overridden by cookie",
+			opts:   []Option{WithCookieDefaults(http.Cookie{Path: "/", SameSite: http.SameSiteStrictMode})},
+			cookie: &http.Cookie{Name: "a", Value: "1", Path: "/admin", HttpOnly: true, SameSite: http.SameSiteLaxMode},
+			want:   "a=1; Path=/admin; HttpOnly; SameSite=Lax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if req == nil {
+				req = httptest.NewRequest("GET", "/", nil)
+			}
+
+			r := New(tt.opts...)
+			r.GET("/", func(ctx Context) error {
+				ctx.SetCookie(tt.cookie)
+				return ctx.String(200, "ok")
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Set-Cookie"); got != tt.want {
+				t.Errorf("Expected Set-Cookie %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestContext_SetCookieRaw(t *testing.T) {
+	r := New()
+	r.GET("/", func(ctx Context) error {
+		ctx.SetCookieRaw(&http.Cookie{Name: "csrf", Value: "t", Path: "/"})
+		ctx.SetCookie(&http.Cookie{Name: "session", Value: "s"})
+		return ctx.String(200, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	want := []string{"csrf=t; Path=/", "session=s; HttpOnly; SameSite=Lax"}
+	if got := w.Header().Values("Set-Cookie"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected Set-Cookie %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,157 @@
+// Package cosantest provides a small client for testing cosan handlers.
+//
+// Requests go through the router's real ServeHTTP, so middleware, hooks,
+// and error handlers are exercised exactly as in production, without the
+// httptest.NewRequest/NewRecorder boilerplate:
+//
+//	func TestGetUser(t *testing.T) {
+//	    client := cosantest.NewClient(t, newRouter())
+//
+//	    resp := client.Get("/users/42")
+//	    if resp.Code != 200 {
+//	        t.Fatalf("unexpected status %d", resp.Code)
+//	    }
+//
+//	    var user User
+//	    resp.JSON(&user)
+//	}
+package cosantest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Client sends requests straight to a handler, typically a cosan.Router.
+type Client struct {
+	// Header is added to every request, e.g. an Authorization header.
+	Header http.Header
+
+	t       testing.TB
+	handler http.Handler
+}
+
+// NewClient returns a Client for handler. Failures to build a request or
+// decode a response fail the test t.
+func NewClient(t testing.TB, handler http.Handler) *Client {
+	return &Client{
+		Header:  make(http.Header),
+		t:       t,
+		handler: handler,
+	}
+}
+
+// Get sends a GET request.
+func (c *Client) Get(path string) *Response {
+	c.t.Helper()
+	return c.Request(http.MethodGet, path, nil)
+}
+
+// Head sends a HEAD request.
+func (c *Client) Head(path string) *Response {
+	c.t.Helper()
+	return c.Request(http.MethodHead, path, nil)
+}
+
+// Delete sends a DELETE request.
+func (c *Client) Delete(path string) *Response {
+	c.t.Helper()
+	return c.Request(http.MethodDelete, path, nil)
+}
+
+// PostJSON sends a POST request with body encoded as JSON.
+func (c *Client) PostJSON(path string, body interface{}) *Response {
+	c.t.Helper()
+	return c.sendJSON(http.MethodPost, path, body)
+}
+
+// PutJSON sends a PUT request with body encoded as JSON.
+func (c *Client) PutJSON(path string, body interface{}) *Response {
+	c.t.Helper()
+	return c.sendJSON(http.MethodPut, path, body)
+}
+
+// PatchJSON sends a PATCH request with body encoded as JSON.
+func (c *Client) PatchJSON(path string, body interface{}) *Response {
+	c.t.Helper()
+	return c.sendJSON(http.MethodPatch, path, body)
+}
+
+// sendJSON encodes body and sends it with a JSON Content-Type.
+func (c *Client) sendJSON(method, path string, body interface{}) *Response {
+	c.t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.t.Fatalf("cosantest: encoding %s %s body: %v", method, path, err)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	return c.Do(req)
+}
+
+// Request sends a request with an optional raw body.
+func (c *Client) Request(method, path string, body io.Reader) *Response {
+	c.t.Helper()
+	return c.Do(httptest.NewRequest(method, path, body))
+}
+
+// Do sends req after adding the client's default headers; headers already
+// set on req take precedence.
+func (c *Client) Do(req *http.Request) *Response {
+	c.t.Helper()
+
+	for key, values := range c.Header {
+		if _, set := req.Header[key]; !set {
+			req.Header[key] = values
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	c.handler.ServeHTTP(rec, req)
+
+	return &Response{
+		Code:   rec.Code,
+		Body:   rec.Body.String(),
+		Header: rec.Header(),
+		t:      c.t,
+	}
+}
+
+// Response is the recorded result of a request.
+type Response struct {
+	// Code is the HTTP status code.
+	Code int
+
+	// Body is the response body.
+	Body string
+
+	// Header holds the response headers.
+	Header http.Header
+
+	t testing.TB
+}
+
+// JSON decodes the body into v, failing the test if it is not valid JSON.
+func (r *Response) JSON(v interface{}) {
+	r.t.Helper()
+
+	if err := json.Unmarshal([]byte(r.Body), v); err != nil {
+		r.t.Fatalf("cosantest: decoding JSON body %q: %v", r.Body, err)
+	}
+}
+
+// HeaderValue returns the first value of the named response header.
+func (r *Response) HeaderValue(key string) string {
+	return r.Header.Get(key)
+}
+
+// ContentType returns the response Content-Type.
+func (r *Response) ContentType() string {
+	return r.Header.Get("Content-Type")
+}
@@ -0,0 +1,79 @@
+package cosantest_test
+
+import (
+	"testing"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/cosantest"
+)
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func newRouter() cosan.Router {
+	router := cosan.New()
+	router.Use(cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+		return func(ctx cosan.Context) error {
+			ctx.Header().Set("X-Middleware", "ran")
+			return next(ctx)
+		}
+	}))
+	router.GET("/users/:id", func(ctx cosan.Context) error {
+		return ctx.JSON(200, user{ID: ctx.Param("id"), Name: ctx.Request().Header.Get("X-User")})
+	})
+	router.POST("/users", func(ctx cosan.Context) error {
+		var u user
+		if err := ctx.Bind(&u); err != nil {
+			return err
+		}
+		u.ID = "new"
+		return ctx.JSON(201, u)
+	})
+	return router
+}
+
+func TestClient_Get(t *testing.T) {
+	client := cosantest.NewClient(t, newRouter())
+	client.Header.Set("X-User", "alice")
+
+	resp := client.Get("/users/42")
+	if resp.Code != 200 {
+		t.Fatalf("Expected 200, got %d", resp.Code)
+	}
+	if resp.HeaderValue("X-Middleware") != "ran" {
+		t.Error("Expected middleware to run")
+	}
+
+	var u user
+	resp.JSON(&u)
+	if u.ID != "42" || u.Name != "alice" {
+		t.Errorf("Unexpected user %+v", u)
+	}
+}
+
+func TestClient_PostJSON(t *testing.T) {
+	client := cosantest.NewClient(t, newRouter())
+
+	resp := client.PostJSON("/users", user{Name: "bob"})
+	if resp.Code != 201 {
+		t.Fatalf("Expected 201, got %d: %s", resp.Code, resp.Body)
+	}
+	if resp.ContentType() != "application/json" {
+		t.Errorf("Expected JSON response, got %q", resp.ContentType())
+	}
+
+	var u user
+	resp.JSON(&u)
+	if u.ID != "new" || u.Name != "bob" {
+		t.Errorf("Unexpected user %+v", u)
+	}
+}
+
+func TestClient_NotFound(t *testing.T) {
+	resp := cosantest.NewClient(t, newRouter()).Delete("/nowhere")
+	if resp.Code != 404 {
+		t.Errorf("Expected 404, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,80 @@
+// Package debug mounts the net/http/pprof and expvar handlers on a cosan
+// router.
+//
+// The handlers are registered as routes under a prefix of your choosing,
+// optionally behind middleware such as authentication, instead of being
+// served from http.DefaultServeMux. It is a separate package because
+// importing net/http/pprof and expvar registers their handlers on
+// http.DefaultServeMux as a side effect; applications that never import it
+// keep that mux clean.
+//
+// Example:
+//
+//	debug.MountProfiler(router, "/admin/pprof", RequireAdmin)
+//	debug.MountExpvar(router, "/admin/vars", RequireAdmin)
+package debug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+)
+
+// DefaultProfilerPrefix is where MountProfiler registers the profiling
+// endpoints when given an empty prefix.
+const DefaultProfilerPrefix = "/debug/pprof"
+
+// MountProfiler registers the pprof endpoints on router under prefix
+// (DefaultProfilerPrefix if empty), each wrapped in the given middleware.
+// Named profiles (heap, goroutine, allocs, ...) are served at
+// prefix/<name>, so they work under any prefix.
+//
+// Long CPU profiles and traces (?seconds=N) need a server WriteTimeout
+// above N; Router.Listen's default is 15 seconds.
+func MountProfiler(router cosan.Router, prefix string, middleware ...cosan.Middleware) {
+	if prefix == "" {
+		prefix = DefaultProfilerPrefix
+	}
+
+	group := router.Group(prefix)
+	handle := func(h http.HandlerFunc) cosan.HandlerFunc {
+		return wrap(fromHTTP(h), middleware)
+	}
+
+	group.GET("/", handle(pprof.Index))
+	group.GET("/cmdline", handle(pprof.Cmdline))
+	group.GET("/profile", handle(pprof.Profile))
+	group.GET("/symbol", handle(pprof.Symbol))
+	group.POST("/symbol", handle(pprof.Symbol))
+	group.GET("/trace", handle(pprof.Trace))
+
+	// pprof.Index only resolves profile names under /debug/pprof/
+	group.GET("/:profile", wrap(func(ctx cosan.Context) error {
+		pprof.Handler(ctx.Param("profile")).ServeHTTP(ctx.Response(), ctx.Request())
+		return nil
+	}, middleware))
+}
+
+// MountExpvar registers the expvar JSON handler on router at path, wrapped
+// in the given middleware.
+func MountExpvar(router cosan.Router, path string, middleware ...cosan.Middleware) {
+	router.GET(path, wrap(fromHTTP(expvar.Handler()), middleware))
+}
+
+// fromHTTP adapts an http.Handler to a cosan.HandlerFunc.
+func fromHTTP(h http.Handler) cosan.HandlerFunc {
+	return func(ctx cosan.Context) error {
+		h.ServeHTTP(ctx.Response(), ctx.Request())
+		return nil
+	}
+}
+
+// wrap applies middleware to handler, the first being outermost.
+func wrap(handler cosan.HandlerFunc, middleware []cosan.Middleware) cosan.HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i].Process(handler)
+	}
+	return handler
+}
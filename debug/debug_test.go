@@ -0,0 +1,71 @@
+package debug_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/debug"
+)
+
+// requireToken rejects requests without the expected token.
+var requireToken = cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+	return func(ctx cosan.Context) error {
+		if ctx.Request().Header.Get("X-Token") != "secret" {
+			return ctx.String(http.StatusUnauthorized, "unauthorized")
+		}
+		return next(ctx)
+	}
+})
+
+func serve(router cosan.Router, path string, authorized bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorized {
+		req.Header.Set("X-Token", "secret")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMountProfiler(t *testing.T) {
+	router := cosan.New()
+	debug.MountProfiler(router, "/admin/pprof", requireToken)
+
+	if w := serve(router, "/admin/pprof/", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", w.Code)
+	}
+
+	if w := serve(router, "/admin/pprof/", true); w.Code != 200 || !strings.Contains(w.Body.String(), "goroutine") {
+		t.Errorf("Expected profile index, got %d", w.Code)
+	}
+	if w := serve(router, "/admin/pprof/goroutine?debug=1", true); w.Code != 200 || !strings.Contains(w.Body.String(), "goroutine profile") {
+		t.Errorf("Expected goroutine profile under custom prefix, got %d %q", w.Code, w.Body.String())
+	}
+	if w := serve(router, "/admin/pprof/cmdline", true); w.Code != 200 {
+		t.Errorf("Expected cmdline, got %d", w.Code)
+	}
+}
+
+func TestMountProfiler_DefaultPrefix(t *testing.T) {
+	router := cosan.New()
+	debug.MountProfiler(router, "")
+
+	if w := serve(router, "/debug/pprof/heap?debug=1", false); w.Code != 200 {
+		t.Errorf("Expected heap profile at default prefix, got %d", w.Code)
+	}
+}
+
+func TestMountExpvar(t *testing.T) {
+	router := cosan.New()
+	debug.MountExpvar(router, "/admin/vars", requireToken)
+
+	if w := serve(router, "/admin/vars", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", w.Code)
+	}
+	if w := serve(router, "/admin/vars", true); w.Code != 200 || !strings.Contains(w.Body.String(), "memstats") {
+		t.Errorf("Expected expvar JSON, got %d", w.Code)
+	}
+}
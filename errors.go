@@ -1,6 +1,10 @@
 package cosan
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 // Common errors returned by the router.
 var (
@@ -12,4 +16,109 @@ var (
 
 	// ErrInvalidPattern is returned for invalid route patterns.
 	ErrInvalidPattern = errors.New("cosan: invalid route pattern")
+
+	// ErrResetNotSupported is returned by Router.Reset unless dynamic routes
+	// are enabled and a built-in matcher is in use.
+	ErrResetNotSupported = errors.New("cosan: Reset requires WithDynamicRoutes and a built-in matcher")
+
+	// ErrNoValidator is returned by Context.Validate when no Validator is configured.
+	ErrNoValidator = errors.New("cosan: no validator configured, use WithValidator")
+
+	// ErrNoProtoCodec is returned for protobuf requests and responses when no
+	// codec is configured.
+	ErrNoProtoCodec = errors.New("cosan: no protobuf codec configured, use WithProtoCodec")
+
+	// ErrNoMsgPackCodec is returned for MessagePack requests and responses
+	// when no codec is configured.
+	ErrNoMsgPackCodec = errors.New("cosan: no msgpack codec configured, use WithMsgPackCodec")
+
+	// ErrInvalidCookieSignature is returned by Context.SignedCookie for cookies
+	// that were tampered with or signed with a different secret.
+	ErrInvalidCookieSignature = errors.New("cosan: invalid cookie signature")
+
+	// ErrNoResponse is passed to the error handler, with WithRequireResponse,
+	// when a handler returns nil without writing a response.
+	ErrNoResponse = errors.New("cosan: handler returned without writing a response")
 )
+
+// PanicError is passed to the error handler when a handler panics and
+// built-in recovery is enabled (see WithRecovery).
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// BindError describes a request value that could not be converted into a
+// struct field during binding.
+type BindError struct {
+	// Field is the name of the struct field.
+	Field string
+
+	// Key is the tag key the value was read from (e.g. "X-Tenant-ID").
+	Key string
+
+	// Err is the underlying conversion error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BindError) Error() string {
+	return fmt.Sprintf("cosan: cannot bind %q to field %s: %v", e.Key, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying conversion error.
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPError is an error carrying the HTTP status code to respond with.
+// Handlers return it to control the status and message produced by the
+// error handler.
+//
+// Example:
+//
+//	return cosan.NewHTTPError(404, "user not found")
+type HTTPError struct {
+	// Code is the HTTP status code.
+	Code int
+
+	// Message is a client-facing description of the error.
+	Message string
+
+	// Err is the optional underlying error. It is not exposed to clients.
+	Err error
+}
+
+// NewHTTPError creates an HTTPError with the given status code and message.
+func NewHTTPError(code int, message string) *HTTPError {
+	return &HTTPError{Code: code, Message: message}
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%d %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%d %s", e.Code, e.Message)
+}
+
+// Unwrap returns the underlying error.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorStatus returns the status code an error should produce: the Code of
+// an *HTTPError anywhere in its chain, otherwise 500. Error handlers use it
+// to default to the status the handler asked for.
+func ErrorStatus(err error) int {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
@@ -200,7 +200,7 @@ func (c *UserController) Update(ctx cosan.Context) error {
 	return ctx.JSON(200, user)
 }
 
-func (c *UserController) Delete(ctx cosan.Context) error {
+func (c *UserController) Destroy(ctx cosan.Context) error {
 	id := 1 // Simplified
 	if err := c.repo.Delete(id); err != nil {
 		return ctx.JSON(500, map[string]string{"error": err.Error()})
@@ -313,18 +313,10 @@ func main() {
 	api := router.Group("/api/v1")
 	api.Use(JSONMiddleware())
 
-	// Users API
-	users := api.Group("/users")
-	users.GET("/:id", userCtrl.Show)
-	users.POST("", userCtrl.Create)
-	users.PUT("/:id", userCtrl.Update)
-	users.DELETE("/:id", userCtrl.Delete)
-
-	// Products API
-	products := api.Group("/products")
-	products.GET("", productCtrl.Index)
-	products.GET("/:id", productCtrl.Show)
-	products.POST("", productCtrl.Create)
+	// REST resources: each controller's Index, Create, Show, Update,
+	// and Destroy methods are routed by convention
+	api.Resource("/users", userCtrl)
+	api.Resource("/products", productCtrl)
 
 	log.Println("Full integration example starting on http://localhost:8080")
 	log.Println("Try:")
@@ -68,7 +68,7 @@ func CreateUserHandler(ctx cosan.Context) error {
 		})
 	}
 
-	// If using datamapper validator:
+	// With a validator configured via cosan.WithValidator:
 	// if err := ctx.Validate(&user); err != nil {
 	//     return ctx.JSON(400, map[string]interface{}{
 	//         "error": "Validation failed",
@@ -173,15 +173,16 @@ func UploadHandler(ctx cosan.Context) error {
 	// var form UploadForm
 	// ctx.BindForm(&form)
 
-	// Manual form handling for now
-	if err := ctx.Request().ParseMultipartForm(10 << 20); err != nil {
+	// Manual form handling for now; the in-memory limit is set with
+	// cosan.WithMaxMultipartMemory
+	if _, err := ctx.MultipartForm(); err != nil {
 		return ctx.JSON(400, map[string]string{
 			"error": "Failed to parse form",
 		})
 	}
 
-	title := ctx.Request().FormValue("title")
-	description := ctx.Request().FormValue("description")
+	title := ctx.FormValue("title")
+	description := ctx.FormValue("description")
 
 	return ctx.JSON(200, map[string]interface{}{
 		"message":     "File uploaded successfully",
@@ -192,29 +193,27 @@ func UploadHandler(ctx cosan.Context) error {
 
 // UpdateProfileHandler demonstrates binding from multiple sources
 func UpdateProfileHandler(ctx cosan.Context) error {
-	id := ctx.Param("id")
-
 	type ProfileUpdate struct {
+		UserID    int    `json:"-" param:"id"`
+		Notify    bool   `json:"-" query:"notify"`
 		FirstName string `json:"first_name"`
 		LastName  string `json:"last_name"`
 		Age       int    `json:"age"`
 	}
 
+	// Path, query, and body all land in one struct
 	var profile ProfileUpdate
-	if err := ctx.Bind(&profile); err != nil {
+	if err := ctx.BindAll(&profile); err != nil {
 		return ctx.JSON(400, map[string]interface{}{
 			"error":   "Invalid profile data",
 			"details": err.Error(),
 		})
 	}
 
-	// Get optional query parameters
-	notify := ctx.Query("notify")
-
 	return ctx.JSON(200, map[string]interface{}{
 		"message": "Profile updated",
-		"user_id": id,
+		"user_id": profile.UserID,
 		"profile": profile,
-		"notify":  notify == "true",
+		"notify":  profile.Notify,
 	})
 }
@@ -193,12 +193,12 @@ func LoginFormHandler(ctx cosan.Context) error {
 
 // LoginHandler processes login form
 func LoginHandler(ctx cosan.Context) error {
-	if err := ctx.Request().ParseForm(); err != nil {
+	if err := ctx.ParseForm(); err != nil {
 		return ctx.JSON(400, map[string]string{"error": "Invalid form"})
 	}
 
-	username := ctx.Request().FormValue("username")
-	password := ctx.Request().FormValue("password")
+	username := ctx.PostForm("username")
+	password := ctx.PostForm("password")
 
 	// Validate credentials (simplified)
 	if username == "admin" && password == "admin123" {
@@ -33,9 +33,8 @@ func main() {
 	admin.GET("/dashboard", DashboardHandler)
 	admin.DELETE("/users/:id", DeleteUserHandler)
 
-	// Route-specific middleware - apply to handler
-	slowHandler := cosan.MiddlewareFunc(TimeoutMiddleware(5 * time.Second)).Process(SlowHandler)
-	router.GET("/slow", slowHandler)
+	// Route-specific timeout - responds 504 if the handler takes too long
+	router.GET("/slow", SlowHandler, cosan.WithTimeout(5*time.Second))
 
 	log.Println("Server starting on http://localhost:8080")
 	log.Fatal(router.Listen(":8080"))
@@ -112,28 +111,6 @@ func AdminMiddleware(next cosan.HandlerFunc) cosan.HandlerFunc {
 	}
 }
 
-// TimeoutMiddleware adds timeout to handler
-func TimeoutMiddleware(timeout time.Duration) cosan.MiddlewareFunc {
-	return func(next cosan.HandlerFunc) cosan.HandlerFunc {
-		return func(ctx cosan.Context) error {
-			done := make(chan error, 1)
-
-			go func() {
-				done <- next(ctx)
-			}()
-
-			select {
-			case err := <-done:
-				return err
-			case <-time.After(timeout):
-				return ctx.JSON(408, map[string]string{
-					"error": "Request timeout",
-				})
-			}
-		}
-	}
-}
-
 // Handlers
 
 func HomeHandler(ctx cosan.Context) error {
@@ -4,7 +4,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"strconv"
 
 	cosan "github.com/toutaio/toutago-cosan-router"
 )
@@ -48,20 +47,20 @@ func main() {
 
 // GetUserHandler demonstrates simple path parameter
 func GetUserHandler(ctx cosan.Context) error {
-	id := ctx.Param("id")
-
-	// Validate and convert parameter
-	userID, err := strconv.Atoi(id)
-	if err != nil {
+	// Bind and convert the parameter
+	var params struct {
+		ID int `param:"id"`
+	}
+	if err := ctx.BindParams(&params); err != nil {
 		return ctx.JSON(400, map[string]string{
 			"error": "Invalid user ID - must be a number",
 		})
 	}
 
 	return ctx.JSON(200, map[string]interface{}{
-		"user_id": userID,
-		"name":    fmt.Sprintf("User %d", userID),
-		"email":   fmt.Sprintf("user%d@example.com", userID),
+		"user_id": params.ID,
+		"name":    fmt.Sprintf("User %d", params.ID),
+		"email":   fmt.Sprintf("user%d@example.com", params.ID),
 	})
 }
 
@@ -76,15 +75,22 @@ func GetPostHandler(ctx cosan.Context) error {
 	})
 }
 
-// GetUserPostHandler demonstrates multiple path parameters
+// GetUserPostHandler demonstrates binding multiple path parameters
 func GetUserPostHandler(ctx cosan.Context) error {
-	userID := ctx.Param("userId")
-	postID := ctx.Param("postId")
+	var params struct {
+		UserID int `param:"userId"`
+		PostID int `param:"postId"`
+	}
+	if err := ctx.BindParams(&params); err != nil {
+		return ctx.JSON(400, map[string]string{
+			"error": "Invalid user or post ID",
+		})
+	}
 
 	return ctx.JSON(200, map[string]interface{}{
-		"user_id": userID,
-		"post_id": postID,
-		"message": fmt.Sprintf("Post %s by user %s", postID, userID),
+		"user_id": params.UserID,
+		"post_id": params.PostID,
+		"message": fmt.Sprintf("Post %d by user %d", params.PostID, params.UserID),
 	})
 }
 
@@ -121,20 +127,23 @@ func StaticFileHandler(ctx cosan.Context) error {
 	})
 }
 
+// productParams holds the path parameters of the product routes
+type productParams struct {
+	ID int `param:"id"`
+}
+
 // GetProductByIDHandler demonstrates RESTful API with path parameter
 func GetProductByIDHandler(ctx cosan.Context) error {
-	id := ctx.Param("id")
-
-	productID, err := strconv.Atoi(id)
-	if err != nil {
+	var params productParams
+	if err := ctx.BindParams(&params); err != nil {
 		return ctx.JSON(400, map[string]string{
 			"error": "Invalid product ID",
 		})
 	}
 
 	product := Product{
-		ID:       productID,
-		Name:     fmt.Sprintf("Product %d", productID),
+		ID:       params.ID,
+		Name:     fmt.Sprintf("Product %d", params.ID),
 		Category: "Electronics",
 	}
 
@@ -143,14 +152,19 @@ func GetProductByIDHandler(ctx cosan.Context) error {
 
 // UpdateProductHandler demonstrates PUT with path parameter
 func UpdateProductHandler(ctx cosan.Context) error {
-	id := ctx.Param("id")
+	var params productParams
+	if err := ctx.BindParams(&params); err != nil {
+		return ctx.JSON(400, map[string]string{
+			"error": "Invalid product ID",
+		})
+	}
 
 	var product Product
 	if err := ctx.Bind(&product); err != nil {
 		return err
 	}
 
-	product.ID, _ = strconv.Atoi(id)
+	product.ID = params.ID
 
 	return ctx.JSON(200, map[string]interface{}{
 		"message": "Product updated",
@@ -185,16 +199,8 @@ func GetIssueHandler(ctx cosan.Context) error {
 func SearchHandler(ctx cosan.Context) error {
 	category := ctx.Param("category")
 	query := ctx.Query("q")
-	page := ctx.Query("page")
-	limit := ctx.Query("limit")
-
-	// Set defaults
-	if page == "" {
-		page = "1"
-	}
-	if limit == "" {
-		limit = "10"
-	}
+	page := ctx.QueryIntDefault("page", 1)
+	limit := ctx.QueryIntDefault("limit", 10)
 
 	return ctx.JSON(200, map[string]interface{}{
 		"category": category,
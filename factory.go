@@ -0,0 +1,95 @@
+package cosan
+
+// ContextFactory supplies the Context passed to middleware, handlers, and
+// error handlers, so frameworks built on the router can extend it with
+// their own fields.
+//
+// The router still creates and pools its own Context for every request,
+// which carries the path parameters, values, and response tracking. Acquire
+// receives it as base and returns the Context handlers will see, which
+// must delegate to base, normally by embedding it, and return it from an
+// Unwrap() Context method so router features such as WithTimeout and group
+// error handlers can find it.
+//
+// Pooling contract: Release is called exactly once for every Context
+// Acquire returned, after the response is complete (for WithTimeout routes,
+// after the handler has returned, which may be later). Once Release is
+// called neither the Context nor base may be used again: base goes back to
+// the router's pool, and Release may put the Context into the factory's
+// own pool.
+//
+// Example:
+//
+//	type AppContext struct {
+//		cosan.Context
+//		User *User
+//	}
+//
+//	func (c *AppContext) Unwrap() cosan.Context { return c.Context }
+//
+//	type appContexts struct{ pool sync.Pool }
+//
+//	func (f *appContexts) Acquire(base cosan.Context) cosan.Context {
+//		c, _ := f.pool.Get().(*AppContext)
+//		if c == nil {
+//			c = &AppContext{}
+//		}
+//		c.Context = base
+//		return c
+//	}
+//
+//	func (f *appContexts) Release(ctx cosan.Context) {
+//		c := ctx.(*AppContext)
+//		*c = AppContext{}
+//		f.pool.Put(c)
+//	}
+//
+//	router := cosan.New(cosan.WithContextFactory(&appContexts{}))
+type ContextFactory interface {
+	// Acquire returns the Context for a request, delegating to base.
+	Acquire(base Context) Context
+
+	// Release is called when the request no longer uses ctx.
+	Release(ctx Context)
+}
+
+// WithContextFactory sets the factory that supplies the Context passed to
+// middleware and handlers. See ContextFactory for the contract it must
+// uphold.
+func WithContextFactory(f ContextFactory) Option {
+	return func(r *router) {
+		r.contextFactory = f
+	}
+}
+
+// handlerContext returns the Context handlers receive for base: base
+// itself, or the ContextFactory's Context for it.
+func (r *router) handlerContext(base *context) Context {
+	if r.contextFactory == nil {
+		return base
+	}
+	return r.contextFactory.Acquire(base)
+}
+
+// releaseHandlerContext hands a Context from handlerContext back to the
+// ContextFactory.
+func (r *router) releaseHandlerContext(ctx Context) {
+	if r.contextFactory != nil {
+		r.contextFactory.Release(ctx)
+	}
+}
+
+// unwrapContext returns the router's own context beneath ctx, following
+// Unwrap through Contexts supplied by a ContextFactory.
+func unwrapContext(ctx Context) (*context, bool) {
+	for {
+		switch c := ctx.(type) {
+		case *context:
+			return c, true
+		case interface{ Unwrap() Context }:
+			ctx = c.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
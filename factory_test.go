@@ -0,0 +1,95 @@
+package cosan
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type appContext struct {
+	Context
+	User string
+}
+
+func (c *appContext) Unwrap() Context { return c.Context }
+
+type countingFactory struct {
+	mu       sync.Mutex
+	acquired int
+	released int
+}
+
+func (f *countingFactory) Acquire(base Context) Context {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.acquired++
+	return &appContext{Context: base}
+}
+
+func (f *countingFactory) Release(ctx Context) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.released++
+	ctx.(*appContext).Context = nil
+}
+
+func TestWithContextFactory(t *testing.T) {
+	factory := &countingFactory{}
+	r := New(WithContextFactory(factory))
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.(*appContext).User = "alice"
+			return next(ctx)
+		}
+	}))
+
+	r.GET("/users/:id", func(ctx Context) error {
+		app := ctx.(*appContext)
+		return ctx.String(200, "%s %s %s", app.User, ctx.Param("id"), ctx.RoutePattern())
+	})
+	r.GET("/slow", func(ctx Context) error {
+		app, ok := ctx.(*appContext)
+		if !ok {
+			t.Fatalf("Expected timeout route to receive the factory's context, got %T", ctx)
+		}
+		return ctx.String(200, "%s slow", app.User)
+	}, WithTimeout(time.Second))
+
+	api := r.Group("/api")
+	api.SetErrorHandler(func(ctx Context, err error) {
+		_ = ctx.String(418, "group handler: %v", err)
+	})
+	api.GET("/fail", func(ctx Context) error {
+		return errors.New("boom")
+	})
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/users/7", 200, "alice 7 /users/:id"},
+		{"/slow", 200, "alice slow"},
+		{"/api/fail", 418, "group handler: boom"},
+		{"/missing", 404, "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.path, tt.code, tt.body, w.Code, w.Body.String())
+		}
+	}
+
+	// The timeout route's handler runs on the request's own context rather
+	// than acquiring a second one
+	if factory.acquired != 4 || factory.released != factory.acquired {
+		t.Errorf("Expected 4 acquired and released contexts, got %d acquired, %d released", factory.acquired, factory.released)
+	}
+}
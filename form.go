@@ -0,0 +1,88 @@
+package cosan
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+)
+
+// DefaultMaxMultipartMemory is the default limit set by
+// WithMaxMultipartMemory, matching net/http's default.
+const DefaultMaxMultipartMemory = 32 << 20
+
+// WithMaxMultipartMemory sets how many bytes of a multipart body are held
+// in memory when parsed by Context.MultipartForm or Context.FormFile
+// (default DefaultMaxMultipartMemory). File parts beyond the limit spill
+// to temporary files, so large uploads still parse without exhausting
+// memory.
+func WithMaxMultipartMemory(n int64) Option {
+	return func(r *router) {
+		r.maxMultipartMemory = n
+	}
+}
+
+// maxMultipartMemory returns the router's multipart memory limit.
+func (c *context) maxMultipartMemory() int64 {
+	if c.router == nil {
+		return DefaultMaxMultipartMemory
+	}
+	return c.router.maxMultipartMemory
+}
+
+// MultipartForm parses a multipart/form-data body and returns the form.
+func (c *context) MultipartForm() (*multipart.Form, error) {
+	if err := c.req.ParseMultipartForm(c.maxMultipartMemory()); err != nil {
+		return nil, err
+	}
+	return c.req.MultipartForm, nil
+}
+
+// FormFile returns the first uploaded file for the named form field.
+func (c *context) FormFile(name string) (*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+
+	files := form.File[name]
+	if len(files) == 0 {
+		return nil, http.ErrMissingFile
+	}
+	return files[0], nil
+}
+
+// FormValue returns the first value for the named field from the body or
+// the query string, like http.Request.FormValue.
+func (c *context) FormValue(key string) string {
+	c.parseForm()
+	return c.req.Form.Get(key)
+}
+
+// PostForm returns the first value for the named body field, like
+// http.Request.PostFormValue.
+func (c *context) PostForm(key string) string {
+	c.parseForm()
+	return c.req.PostForm.Get(key)
+}
+
+// ParseForm parses the url-encoded or multipart form read by FormValue and
+// PostForm, returning the parse error, which is cached with the form.
+func (c *context) ParseForm() error {
+	return c.parseForm()
+}
+
+// parseForm parses the url-encoded or multipart form once; the request
+// caches the form and c the error. ParseForm runs first because
+// ParseMultipartForm drops its error for a body that is not multipart.
+func (c *context) parseForm() error {
+	if !c.formParsed || c.req.Form == nil {
+		err := c.req.ParseForm()
+		multipartErr := c.req.ParseMultipartForm(c.maxMultipartMemory())
+		if multipartErr != nil && !errors.Is(multipartErr, http.ErrNotMultipart) {
+			err = multipartErr
+		}
+		c.formErr = err
+		c.formParsed = true
+	}
+	return c.formErr
+}
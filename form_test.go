@@ -0,0 +1,132 @@
+package cosan
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// multipartRequest builds an upload with a title field and a file part.
+func multipartRequest(t *testing.T, content []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "report")
+	fw, err := mw.CreateFormFile("file", "report.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(content)
+	mw.Close()
+
+	req := httptest.NewRequest("POST", "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestWithMaxMultipartMemory(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 64<<10)
+
+	r := New(WithMaxMultipartMemory(1 << 10))
+	r.POST("/upload", func(ctx Context) error {
+		fh, err := ctx.FormFile("file")
+		if err != nil {
+			return err
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if _, onDisk := f.(*os.File); !onDisk {
+			t.Error("Expected file beyond the memory limit to spill to disk")
+		}
+		data, _ := io.ReadAll(f)
+
+		form, _ := ctx.MultipartForm()
+		defer form.RemoveAll()
+		return ctx.String(200, "%s:%d", form.Value["title"][0], len(data))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, multipartRequest(t, content))
+
+	if w.Code != 200 || w.Body.String() != "report:65536" {
+		t.Errorf("Expected 200 'report:65536', got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestFormFile_Missing(t *testing.T) {
+	ctx := newContext(httptest.NewRecorder(), multipartRequest(t, []byte("x")), nil)
+	if _, err := ctx.FormFile("other"); !errors.Is(err, http.ErrMissingFile) {
+		t.Errorf("Expected http.ErrMissingFile, got %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	ctx = newContext(httptest.NewRecorder(), req, nil)
+	if _, err := ctx.MultipartForm(); err == nil {
+		t.Error("Expected an error for a non-multipart body")
+	}
+}
+
+func TestContext_FormValue(t *testing.T) {
+	req := httptest.NewRequest("POST", "/login?user=query&next=/home", strings.NewReader("user=body&password=secret"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	if got := ctx.FormValue("user"); got != "body" {
+		t.Errorf("FormValue(user) = %q, want body value first", got)
+	}
+	if got := ctx.FormValue("next"); got != "/home" {
+		t.Errorf("FormValue(next) = %q, want query value", got)
+	}
+	if got := ctx.PostForm("password"); got != "secret" {
+		t.Errorf("PostForm(password) = %q, want secret", got)
+	}
+	if got := ctx.PostForm("next"); got != "" {
+		t.Errorf("PostForm(next) = %q, want query ignored", got)
+	}
+}
+
+func TestContext_ParseForm(t *testing.T) {
+	req := httptest.NewRequest("POST", "/login", strings.NewReader("user=%zz"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	if got := ctx.PostForm("user"); got != "" {
+		t.Errorf("PostForm(user) = %q, want empty for a malformed body", got)
+	}
+	if err := ctx.ParseForm(); err == nil {
+		t.Error("Expected the cached parse error after reading a value")
+	}
+
+	req = httptest.NewRequest("POST", "/login", strings.NewReader("user=alice"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ctx = newContext(httptest.NewRecorder(), req, nil)
+	if err := ctx.ParseForm(); err != nil {
+		t.Errorf("Expected a url-encoded body to parse, got %v", err)
+	}
+	if got := ctx.PostForm("user"); got != "alice" {
+		t.Errorf("PostForm(user) = %q, want alice", got)
+	}
+}
+
+func TestContext_FormValue_Multipart(t *testing.T) {
+	ctx := newContext(httptest.NewRecorder(), multipartRequest(t, []byte("data")), nil)
+
+	if got := ctx.PostForm("title"); got != "report" {
+		t.Errorf("PostForm(title) = %q, want report", got)
+	}
+	if _, err := ctx.FormFile("file"); err != nil {
+		t.Errorf("Expected file after form values were read, got %v", err)
+	}
+}
@@ -7,33 +7,67 @@ import (
 	"testing"
 )
 
+// fuzzRoutes are registered by the path fuzzers: static, parameter,
+// optional, and wildcard routes sharing prefixes.
+var fuzzRoutes = []string{
+	"/",
+	"/users",
+	"/users/:id",
+	"/users/:id/posts/:post",
+	"/users/me",
+	"/files/*filepath",
+	"/posts/:id/:slug?",
+	"/a/b/c/d/e/f/g/*rest",
+	"/ünïcödé/:name",
+	"/*catchall",
+}
+
+// fuzzPathSeeds are degenerate paths: empty segments, duplicate and
+// trailing slashes, dot segments, unicode, and deep nesting.
+var fuzzPathSeeds = []string{
+	"/users",
+	"/users/123",
+	"/api/v1/posts",
+	"/",
+	"",
+	"//",
+	"///users",
+	"/users//",
+	"/users//posts",
+	"/../",
+	"/users/../admin",
+	"/files/./../etc/passwd",
+	"/files/",
+	"/files",
+	"/posts//",
+	"/a/b/c/d/e/f/g/",
+	"/ünïcödé/名前",
+	"/\xff\xfe/\x00",
+	"users",
+	":id",
+	"*",
+	"/" + strings.Repeat("a/", 512),
+	strings.Repeat("/", 1024),
+}
+
 // FuzzRouterPath tests router with various path inputs
 func FuzzRouterPath(f *testing.F) {
-	// Seed corpus with known paths
-	f.Add("/users")
-	f.Add("/users/123")
-	f.Add("/api/v1/posts")
-	f.Add("/")
-	f.Add("")
+	for _, seed := range fuzzPathSeeds {
+		f.Add(seed)
+	}
 
 	f.Fuzz(func(t *testing.T, path string) {
-		// Skip empty paths as httptest.NewRequest doesn't accept them
-		if path == "" {
-			path = "/"
-		}
-
 		router := New()
+		for _, pattern := range fuzzRoutes {
+			router.GET(pattern, func(ctx Context) error {
+				return ctx.String(200, "%s", ctx.Param("id"))
+			})
+		}
 
-		router.GET("/users", func(ctx Context) error {
-			return ctx.String(200, "users")
-		})
-
-		router.GET("/users/:id", func(ctx Context) error {
-			return ctx.String(200, "user")
-		})
-
-		// Try to match the path - should not panic
-		req := httptest.NewRequest("GET", path, nil)
+		// Set the path directly: httptest.NewRequest rejects many of the
+		// inputs the matcher must survive
+		req := httptest.NewRequest("GET", "/", nil)
+		req.URL.Path = path
 		w := httptest.NewRecorder()
 
 		defer func() {
@@ -46,6 +80,36 @@ func FuzzRouterPath(f *testing.F) {
 	})
 }
 
+// FuzzRadixMatch calls the matcher directly, so paths longer than
+// the router's path length limit reach search too.
+func FuzzRadixMatch(f *testing.F) {
+	for _, seed := range fuzzPathSeeds {
+		f.Add(seed)
+	}
+
+	m := newRadixMatcher()
+	for _, pattern := range fuzzRoutes {
+		if err := m.Register("GET", pattern, func(ctx Context) error { return nil }); err != nil {
+			f.Fatal(err)
+		}
+	}
+	if err := m.Compile(); err != nil {
+		f.Fatal(err)
+	}
+
+	f.Fuzz(func(t *testing.T, path string) {
+		rt, params, found := m.Match("GET", path)
+		if found && rt == nil {
+			t.Errorf("Match(%q) reported a match without a route", path)
+		}
+		for name, value := range params {
+			if !strings.Contains(path, value) {
+				t.Errorf("Match(%q) captured %s=%q, which is not part of the path", path, name, value)
+			}
+		}
+	})
+}
+
 // FuzzJSONInput tests JSON parsing with various inputs
 func FuzzJSONInput(f *testing.F) {
 	// Seed corpus
@@ -0,0 +1,12 @@
+module github.com/toutaio/toutago-cosan-router/h2c
+
+go 1.22
+
+require (
+	github.com/toutaio/toutago-cosan-router v1.1.0
+	golang.org/x/net v0.35.0
+)
+
+require golang.org/x/text v0.22.0 // indirect
+
+replace github.com/toutaio/toutago-cosan-router => ../
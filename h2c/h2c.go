@@ -0,0 +1,42 @@
+// Package h2c serves a cosan router over cleartext HTTP/2 (h2c), e.g.
+// behind a load balancer or service mesh that speaks HTTP/2 without TLS.
+//
+// It lives in its own module so the core router stays dependency-free;
+// only applications that import this package pull in golang.org/x/net.
+//
+// TLS needs no extra package: Router.ListenTLS negotiates HTTP/2 through
+// ALPN automatically.
+//
+// Example:
+//
+//	router := cosan.New()
+//	log.Fatal(h2c.Listen(":8080", router))
+package h2c
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Handler wraps handler so it accepts h2c connections, both with prior
+// knowledge and via the HTTP/1.1 Upgrade header, while still serving
+// HTTP/1.1 requests.
+func Handler(handler http.Handler) http.Handler {
+	return h2c.NewHandler(handler, &http2.Server{})
+}
+
+// Listen serves handler (typically a cosan.Router) over h2c and HTTP/1.1 on
+// addr, with the same timeout defaults as Router.Listen.
+func Listen(addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      Handler(handler),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,64 @@
+package h2c_test
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/http2"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/h2c"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	router := cosan.New()
+	router.GET("/proto", func(ctx cosan.Context) error {
+		return ctx.String(200, ctx.Request().Proto)
+	})
+
+	server := httptest.NewServer(h2c.Handler(router))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestHandler_PriorKnowledge(t *testing.T) {
+	server := newServer(t)
+
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+
+	if proto := get(t, client, server.URL+"/proto"); proto != "HTTP/2.0" {
+		t.Errorf("Expected HTTP/2.0, got %q", proto)
+	}
+}
+
+func TestHandler_HTTP1Fallback(t *testing.T) {
+	server := newServer(t)
+
+	if proto := get(t, http.DefaultClient, server.URL+"/proto"); proto != "HTTP/1.1" {
+		t.Errorf("Expected HTTP/1.1, got %q", proto)
+	}
+}
@@ -0,0 +1,132 @@
+package cosan
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// hashMatcher implements Matcher with one hash map per HTTP method.
+// It only supports static routes, trading path parameters for O(1) lookups.
+type hashMatcher struct {
+	mu       sync.RWMutex
+	routes   map[string]map[string]*route // method -> path -> route
+	compiled bool
+	dynamic  bool
+	count    int // Registration counter for ordering Routes()
+}
+
+// NewHashMatcher creates a Matcher optimized for applications whose routes
+// are all static (no :param or *wildcard segments). Lookups are a single
+// map access per request, with no tree traversal.
+//
+// Choose the default radix matcher when any route has parameters or
+// wildcards; registering such a pattern with the hash matcher fails with
+// ErrInvalidPattern.
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithMatcher(cosan.NewHashMatcher()))
+//	router.GET("/health", HealthHandler)
+//	router.GET("/api/status", StatusHandler)
+func NewHashMatcher() Matcher {
+	return &hashMatcher{
+		routes: make(map[string]map[string]*route),
+	}
+}
+
+// Register adds a static route to the matcher.
+func (m *hashMatcher) Register(method, pattern string, handler HandlerFunc) error {
+	return m.insert(&route{
+		method:  method,
+		pattern: pattern,
+		handler: handler,
+	})
+}
+
+// insert adds an existing route to the matcher.
+func (m *hashMatcher) insert(rt *route) error {
+	if strings.ContainsAny(rt.pattern, ":*") {
+		return fmt.Errorf("%w: hash matcher supports static routes only: %s", ErrInvalidPattern, rt.pattern)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.compiled && !m.dynamic {
+		return ErrRouterAlreadyCompiled
+	}
+
+	paths := m.routes[rt.method]
+	if paths == nil {
+		paths = make(map[string]*route)
+		m.routes[rt.method] = paths
+	}
+	if _, exists := paths[rt.pattern]; exists {
+		return ErrConflictingRoutes
+	}
+
+	rt.seq = m.count
+	m.count++
+	paths[rt.pattern] = rt
+
+	return nil
+}
+
+// Match finds the static route registered for the method and path.
+// Static routes carry no parameters, so the returned map is nil.
+func (m *hashMatcher) Match(method, path string) (Route, map[string]string, bool) {
+	if m.dynamic {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+	}
+
+	rt, found := m.routes[method][path]
+	if !found {
+		return nil, nil, false
+	}
+
+	return rt, nil, true
+}
+
+// Routes returns all registered routes in registration order.
+func (m *hashMatcher) Routes() []Route {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	routes := make([]*route, 0, m.count)
+	for _, paths := range m.routes {
+		for _, rt := range paths {
+			routes = append(routes, rt)
+		}
+	}
+
+	return sortedRoutes(routes)
+}
+
+// setDynamic allows registration after Compile.
+func (m *hashMatcher) setDynamic(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dynamic = enabled
+}
+
+// Compile marks the matcher ready for serving; maps need no preparation.
+func (m *hashMatcher) Compile() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.compiled = true
+	return nil
+}
+
+// reset drops every route and clears the compiled state.
+func (m *hashMatcher) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.routes = make(map[string]map[string]*route)
+	m.compiled = false
+	m.count = 0
+}
@@ -0,0 +1,86 @@
+package cosan
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHashMatcher_Match(t *testing.T) {
+	m := NewHashMatcher()
+	handler := func(ctx Context) error { return nil }
+
+	m.Register("GET", "/users", handler)
+	m.Register("POST", "/users", handler)
+	m.Register("GET", "/api/status", handler)
+	m.Compile()
+
+	tests := []struct {
+		method      string
+		path        string
+		shouldMatch bool
+	}{
+		{"GET", "/users", true},
+		{"POST", "/users", true},
+		{"GET", "/api/status", true},
+		{"DELETE", "/users", false},
+		{"GET", "/users/1", false},
+		{"GET", "/api", false},
+	}
+
+	for _, tt := range tests {
+		route, _, ok := m.Match(tt.method, tt.path)
+		if ok != tt.shouldMatch {
+			t.Errorf("Match(%s, %s) = %v, want %v", tt.method, tt.path, ok, tt.shouldMatch)
+		}
+		if ok && route.Pattern() != tt.path {
+			t.Errorf("Match(%s, %s) returned pattern %s", tt.method, tt.path, route.Pattern())
+		}
+	}
+}
+
+func TestHashMatcher_RejectsDynamicPatterns(t *testing.T) {
+	m := NewHashMatcher()
+	handler := func(ctx Context) error { return nil }
+
+	for _, pattern := range []string{"/users/:id", "/files/*path"} {
+		if err := m.Register("GET", pattern, handler); !errors.Is(err, ErrInvalidPattern) {
+			t.Errorf("Register(%s) = %v, want ErrInvalidPattern", pattern, err)
+		}
+	}
+}
+
+func TestHashMatcher_Duplicate(t *testing.T) {
+	m := NewHashMatcher()
+	handler := func(ctx Context) error { return nil }
+
+	m.Register("GET", "/users", handler)
+	if err := m.Register("GET", "/users", handler); !errors.Is(err, ErrConflictingRoutes) {
+		t.Errorf("Expected ErrConflictingRoutes, got %v", err)
+	}
+}
+
+func TestHashMatcher_WithRouter(t *testing.T) {
+	router := New(WithMatcher(NewHashMatcher()))
+	router.GET("/health", func(ctx Context) error {
+		return ctx.String(200, "ok")
+	}, WithName("health"))
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Body.String() != "ok" {
+		t.Errorf("Expected 200 ok, got %d %q", w.Code, w.Body.String())
+	}
+	if router.FindRoute("health") == nil {
+		t.Error("Expected route metadata to be available from the hash matcher")
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != 404 {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}
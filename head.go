@@ -0,0 +1,78 @@
+package cosan
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// headWriter serves a HEAD request with a GET route's handler. The body is
+// discarded but counted, and the status is held back until the handler
+// returns, so Content-Length can report the size of the body a GET would
+// have sent. A handler that flushes is streaming: the headers are sent at
+// the flush, without Content-Length.
+type headWriter struct {
+	http.ResponseWriter
+	code      int
+	size      int64
+	committed bool // headers sent to the underlying writer
+}
+
+func (w *headWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *headWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	w.size += int64(len(b))
+	return len(b), nil
+}
+
+// Flush sends the headers without Content-Length, since the body size is
+// not known yet.
+func (w *headWriter) Flush() {
+	w.commit()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish sends the held-back headers once the handler has returned, with
+// Content-Length set to the discarded body's size unless the handler set
+// it or streamed.
+func (w *headWriter) finish() {
+	if w.committed || w.code == 0 {
+		return
+	}
+
+	header := w.Header()
+	if header.Get("Content-Length") == "" && header.Get("Transfer-Encoding") == "" && bodyAllowedForStatus(w.code) {
+		header.Set("Content-Length", strconv.FormatInt(w.size, 10))
+	}
+	w.commit()
+}
+
+// commit sends the status to the underlying writer once.
+func (w *headWriter) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.code)
+}
+
+// bodyAllowedForStatus reports whether a response with the status may
+// carry a body, and so a Content-Length.
+func bodyAllowedForStatus(code int) bool {
+	return code >= 200 && code != http.StatusNoContent && code != http.StatusNotModified
+}
@@ -0,0 +1,57 @@
+package cosan
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestHEADFallback(t *testing.T) {
+	r := New()
+	r.GET("/users/:id", func(ctx Context) error {
+		return ctx.JSON(200, map[string]string{"id": ctx.Param("id")})
+	})
+	r.GET("/stream", func(ctx Context) error {
+		ctx.Response().WriteHeader(200)
+		_, _ = ctx.Response().Write([]byte("chunk"))
+		return ctx.Flush()
+	})
+	r.HEAD("/own", func(ctx Context) error {
+		ctx.Response().Header().Set("X-Own", "yes")
+		ctx.Response().WriteHeader(204)
+		return nil
+	})
+
+	get := httptest.NewRecorder()
+	r.ServeHTTP(get, httptest.NewRequest("GET", "/users/7", nil))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("HEAD", "/users/7", nil))
+	if w.Code != 200 || w.Body.Len() != 0 {
+		t.Errorf("Expected 200 with an empty body, got %d %q", w.Code, w.Body.String())
+	}
+	if got, want := w.Header().Get("Content-Length"), strconv.Itoa(get.Body.Len()); got != want {
+		t.Errorf("Expected Content-Length %s, got %q", want, got)
+	}
+	if w.Header().Get("Content-Type") != get.Header().Get("Content-Type") {
+		t.Errorf("Expected the GET route's Content-Type, got %q", w.Header().Get("Content-Type"))
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("HEAD", "/stream", nil))
+	if w.Code != 200 || w.Body.Len() != 0 || w.Header().Get("Content-Length") != "" {
+		t.Errorf("Expected a streamed 200 without body or Content-Length, got %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Length"))
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("HEAD", "/own", nil))
+	if w.Code != 204 || w.Header().Get("X-Own") != "yes" {
+		t.Errorf("Expected the HEAD route to win, got %d %q", w.Code, w.Header().Get("X-Own"))
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("HEAD", "/missing", nil))
+	if w.Code != 404 {
+		t.Errorf("Expected 404 for HEAD without a GET route, got %d", w.Code)
+	}
+}
@@ -0,0 +1,158 @@
+package cosan
+
+import (
+	stdcontext "context"
+	"log"
+	"net/http"
+)
+
+// WithReadiness sets a function reporting whether the application has
+// finished starting up (migrations applied, caches warmed). Until it first
+// returns true, Health endpoints respond 503 without running their checks,
+// so a load balancer keeps traffic away from the instance; with
+// WithReadinessGate every other route responds 503 as well. Once ready
+// returns true the router stays ready and ready is not called again.
+//
+// Example:
+//
+//	var migrated atomic.Bool
+//	router := cosan.New(cosan.WithReadiness(migrated.Load), cosan.WithReadinessGate(true))
+//	router.Health("/readyz")
+//	go func() { runMigrations(); migrated.Store(true) }()
+func WithReadiness(ready func() bool) Option {
+	return func(r *router) {
+		r.readiness = ready
+	}
+}
+
+// WithReadinessGate makes every route except Health and Liveness endpoints
+// respond 503 Service Unavailable, with Retry-After, until the WithReadiness
+// function first returns true. It has no effect without WithReadiness.
+func WithReadinessGate(enabled bool) Option {
+	return func(r *router) {
+		r.readinessGate = enabled
+	}
+}
+
+// isReady reports whether startup has completed, latching the first true
+// result of the readiness function.
+func (r *router) isReady() bool {
+	if r.readiness == nil || r.ready.Load() {
+		return true
+	}
+	if r.readiness() {
+		r.ready.Store(true)
+		return true
+	}
+	return false
+}
+
+// ungated exempts a route from the readiness gate.
+func ungated() RouteOption {
+	return func(r *route) {
+		r.ungated = true
+	}
+}
+
+// readinessHandler responds 503 until the router is ready.
+func readinessHandler(next HandlerFunc, r *router) HandlerFunc {
+	return func(ctx Context) error {
+		if !r.isReady() {
+			ctx.Header().Set("Retry-After", "1")
+			return NewHTTPError(http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+		}
+		return next(ctx)
+	}
+}
+
+// HealthCheck is a named dependency check run by a health endpoint.
+// Check should respect the context, which is canceled with the request.
+type HealthCheck struct {
+	// Name identifies the check in the health report.
+	Name string
+
+	// Check returns nil when the dependency is healthy.
+	Check func(ctx stdcontext.Context) error
+}
+
+// HealthReport is the JSON body returned by health endpoints.
+type HealthReport struct {
+	// Status is "ok" when every check passed, "unavailable" otherwise.
+	Status string `json:"status"`
+
+	// Checks lists the result of each check in registration order.
+	Checks []HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthCheckResult is the outcome of a single HealthCheck.
+type HealthCheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // "ok" or "fail"
+}
+
+// Health statuses used in health reports.
+const (
+	healthOK          = "ok"
+	healthFail        = "fail"
+	healthUnavailable = "unavailable"
+)
+
+// Health registers a GET readiness endpoint that runs every check and
+// responds 200 when all pass, or 503 when any fails or the WithReadiness
+// function has not yet passed. The JSON report lists each check's name and
+// status; failure details are logged rather than exposed to clients.
+//
+// Example:
+//
+//	router.Health("/readyz", cosan.HealthCheck{Name: "db", Check: db.PingContext})
+func (r *router) Health(path string, checks ...HealthCheck) {
+	r.GET(path, healthHandler(r, checks), ungated())
+}
+
+// Liveness registers a GET endpoint that responds 200 whenever the process
+// is able to serve requests.
+//
+// Example:
+//
+//	router.Liveness("/livez")
+func (r *router) Liveness(path string) {
+	r.GET(path, livenessHandler, ungated())
+}
+
+// healthHandler runs the checks in order and reports their results, once
+// the router is ready.
+func healthHandler(r *router, checks []HealthCheck) HandlerFunc {
+	return func(ctx Context) error {
+		if !r.isReady() {
+			ctx.Header().Set("Cache-Control", "no-store")
+			return ctx.JSON(http.StatusServiceUnavailable, HealthReport{Status: healthUnavailable})
+		}
+
+		report := HealthReport{
+			Status: healthOK,
+			Checks: make([]HealthCheckResult, 0, len(checks)),
+		}
+
+		for _, check := range checks {
+			result := HealthCheckResult{Name: check.Name, Status: healthOK}
+			if err := check.Check(ctx.Request().Context()); err != nil {
+				log.Printf("cosan: health check %q failed: %v", check.Name, err)
+				result.Status = healthFail
+				report.Status = healthUnavailable
+			}
+			report.Checks = append(report.Checks, result)
+		}
+
+		ctx.Header().Set("Cache-Control", "no-store")
+		if report.Status != healthOK {
+			return ctx.JSON(http.StatusServiceUnavailable, report)
+		}
+		return ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// livenessHandler always reports the process as up.
+func livenessHandler(ctx Context) error {
+	ctx.Header().Set("Cache-Control", "no-store")
+	return ctx.JSON(http.StatusOK, HealthReport{Status: healthOK})
+}
@@ -0,0 +1,113 @@
+package cosan
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_Health(t *testing.T) {
+	dbUp := true
+	r := New()
+	r.Health("/readyz",
+		HealthCheck{Name: "db", Check: func(stdcontext.Context) error {
+			if !dbUp {
+				return errors.New("connection refused")
+			}
+			return nil
+		}},
+		HealthCheck{Name: "cache", Check: func(stdcontext.Context) error { return nil }},
+	)
+	r.Liveness("/livez")
+
+	get := func(path string) (int, HealthReport) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		var report HealthReport
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Fatalf("Invalid report for %s: %v", path, err)
+		}
+		return w.Code, report
+	}
+
+	code, report := get("/readyz")
+	if code != 200 || report.Status != "ok" || len(report.Checks) != 2 {
+		t.Errorf("Expected healthy report, got %d %+v", code, report)
+	}
+
+	dbUp = false
+	code, report = get("/readyz")
+	if code != 503 || report.Status != "unavailable" {
+		t.Errorf("Expected 503 unavailable, got %d %+v", code, report)
+	}
+	want := []HealthCheckResult{{Name: "db", Status: "fail"}, {Name: "cache", Status: "ok"}}
+	for i, result := range report.Checks {
+		if result != want[i] {
+			t.Errorf("Check %d: expected %+v, got %+v", i, want[i], result)
+		}
+	}
+
+	code, report = get("/livez")
+	if code != 200 || report.Status != "ok" {
+		t.Errorf("Expected liveness 200 ok, got %d %+v", code, report)
+	}
+}
+
+func TestWithReadiness(t *testing.T) {
+	ready, calls := false, 0
+	readiness := func() bool {
+		calls++
+		return ready
+	}
+
+	gated := New(WithReadiness(readiness), WithReadinessGate(true))
+	gated.Health("/readyz")
+	gated.Group("/ops").Liveness("/livez")
+	gated.GET("/users", func(ctx Context) error { return ctx.String(200, "users") })
+
+	ungatedRouter := New(WithReadiness(readiness))
+	ungatedRouter.Health("/readyz")
+	ungatedRouter.GET("/users", func(ctx Context) error { return ctx.String(200, "users") })
+
+	get := func(r Router, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	if w := get(gated, "/readyz"); w.Code != 503 {
+		t.Errorf("Expected readiness endpoint to respond 503 before ready, got %d", w.Code)
+	}
+	if w := get(gated, "/ops/livez"); w.Code != 200 {
+		t.Errorf("Expected liveness endpoint to respond 200 before ready, got %d", w.Code)
+	}
+	if w := get(gated, "/users"); w.Code != 503 || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected gated route to respond 503 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+	if w := get(ungatedRouter, "/users"); w.Code != 200 {
+		t.Errorf("Expected route without WithReadinessGate to be served, got %d", w.Code)
+	}
+	if w := get(ungatedRouter, "/readyz"); w.Code != 503 {
+		t.Errorf("Expected readiness endpoint to respond 503 without the gate, got %d", w.Code)
+	}
+
+	ready = true
+	if w := get(gated, "/users"); w.Code != 200 {
+		t.Errorf("Expected gated route to be served once ready, got %d", w.Code)
+	}
+	if w := get(gated, "/readyz"); w.Code != 200 {
+		t.Errorf("Expected readiness endpoint to respond 200 once ready, got %d", w.Code)
+	}
+
+	// Readiness latches: later failures do not take the router out of service
+	ready = false
+	before := calls
+	if w := get(gated, "/users"); w.Code != 200 {
+		t.Errorf("Expected router to stay ready, got %d", w.Code)
+	}
+	if calls != before {
+		t.Errorf("Expected readiness not to be called once passed, got %d more calls", calls-before)
+	}
+}
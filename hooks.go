@@ -1,12 +1,18 @@
 package cosan
 
-import "net/http"
+import (
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
 
 // hooks stores router-level hooks for lifecycle events
 type hooks struct {
 	beforeRequest []RequestHook
 	afterResponse []ResponseHook
 	errorHandler  ErrorHandler
+	onError       []ErrorHandler
 }
 
 // BeforeRequest registers a hook to run before each request
@@ -31,6 +37,19 @@ func (r *router) AfterResponse(hook ResponseHook) {
 	r.hooks.afterResponse = append(r.hooks.afterResponse, hook)
 }
 
+// OnError registers an observer for every error that reaches the error
+// handler. Observers run in registration order before the response is
+// rendered, and also see errors returned after the response was committed.
+func (r *router) OnError(observer ErrorHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hooks == nil {
+		r.hooks = &hooks{}
+	}
+	r.hooks.onError = append(r.hooks.onError, observer)
+}
+
 // SetErrorHandler sets a custom error handler for the router
 func (r *router) SetErrorHandler(handler ErrorHandler) {
 	r.mu.Lock()
@@ -57,6 +76,27 @@ func (r *router) executeBeforeHooks(req *http.Request) error {
 	return nil
 }
 
+// rejectRequest responds to a request aborted by a before-request hook,
+// without matching a route. The error goes to the error handler, so an
+// *HTTPError responds with its status (e.g. 403 or 503) while other errors
+// produce 500; after-response hooks then observe the written status.
+func (r *router) rejectRequest(w http.ResponseWriter, req *http.Request, err error) {
+	ctx := acquireContextFrom(r.contextPool, w, req)
+	ctx.router = r
+	defer releaseContext(ctx)
+
+	recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	ctx.res = recorder
+	ctx.recorder = recorder
+
+	hctx := r.handlerContext(ctx)
+	defer r.releaseHandlerContext(hctx)
+
+	r.handleError(hctx, err)
+
+	r.executeAfterHooks(req, recorder.statusCode)
+}
+
 // executeAfterHooks runs all after-response hooks
 func (r *router) executeAfterHooks(req *http.Request, statusCode int) {
 	if r.hooks == nil {
@@ -64,17 +104,110 @@ func (r *router) executeAfterHooks(req *http.Request, statusCode int) {
 	}
 
 	for _, hook := range r.hooks.afterResponse {
-		hook(req, statusCode)
+		r.runAfterHook(hook, req, statusCode)
+	}
+}
+
+// runAfterHook runs a single after-response hook. When recovery is enabled,
+// a panic is logged with its stack trace and the remaining hooks still run:
+// the response is already sent, so there is nothing left to report it to.
+func (r *router) runAfterHook(hook ResponseHook, req *http.Request, statusCode int) {
+	if r.recovery {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("cosan: panic in after-response hook for %s %s: %v\n%s", req.Method, req.URL.Path, rec, debug.Stack())
+			}
+		}()
+	}
+
+	hook(req, statusCode)
+}
+
+// errorHandlerFor returns the error handler of the innermost group of the
+// matched route that has one, falling back to the router-level handler.
+func (r *router) errorHandlerFor(ctx Context) ErrorHandler {
+	if c, ok := unwrapContext(ctx); ok && c.route != nil {
+		for g := c.route.group; g != nil; g = g.parent {
+			if g.errorHandler != nil {
+				return g.errorHandler
+			}
+		}
+	}
+
+	if r.hooks != nil {
+		return r.hooks.errorHandler
 	}
+	return nil
 }
 
-// handleError handles errors using custom handler if set
+// handleError handles errors using custom handler if set.
+// If the handler already committed a response, writing an error response
+// would corrupt it, so the error is only logged.
 func (r *router) handleError(ctx Context, err error) {
-	if r.hooks != nil && r.hooks.errorHandler != nil {
-		r.hooks.errorHandler(ctx, err)
+	r.notifyErrorObservers(ctx, err)
+
+	if c, ok := unwrapContext(ctx); ok && c.committed() {
+		req := ctx.Request()
+		log.Printf("cosan: error after response was committed for %s %s: %v", req.Method, req.URL.Path, err)
+		return
+	}
+
+	if handler := r.errorHandlerFor(ctx); handler != nil {
+		handler(ctx, err)
+		return
+	}
+
+	defaultErrorHandler(ctx, err)
+}
+
+// notifyErrorObservers runs the OnError observers. As with after-response
+// hooks, a panicking observer is logged when recovery is enabled and the
+// remaining observers still run.
+func (r *router) notifyErrorObservers(ctx Context, err error) {
+	if r.hooks == nil {
 		return
 	}
 
-	// Default error handling
-	_ = ctx.String(500, "Internal Server Error: "+err.Error())
+	for _, observer := range r.hooks.onError {
+		r.runErrorObserver(observer, ctx, err)
+	}
+}
+
+// runErrorObserver runs a single OnError observer.
+func (r *router) runErrorObserver(observer ErrorHandler, ctx Context, err error) {
+	if r.recovery {
+		defer func() {
+			if rec := recover(); rec != nil {
+				req := ctx.Request()
+				log.Printf("cosan: panic in error observer for %s %s: %v\n%s", req.Method, req.URL.Path, rec, debug.Stack())
+			}
+		}()
+	}
+
+	observer(ctx, err)
+}
+
+// defaultErrorHandler responds with the status and message of an
+// *HTTPError, or 500 for any other error. A recovered panic gets a plain
+// "Internal Server Error", so its value never reaches the client. Clients
+// that prefer JSON over plain text in their Accept header get
+// {"error": message}; everyone else, including clients without an Accept
+// header, gets plain text.
+func defaultErrorHandler(ctx Context, err error) {
+	code, message := http.StatusInternalServerError, "Internal Server Error: "+err.Error()
+	var httpErr *HTTPError
+	var panicErr *PanicError
+	switch {
+	case errors.As(err, &httpErr):
+		code, message = httpErr.Code, httpErr.Message
+	case errors.As(err, &panicErr):
+		message = http.StatusText(http.StatusInternalServerError)
+	}
+
+	offers := []string{"text/plain", "application/json"}
+	if negotiateMediaType(ctx.Request().Header.Get("Accept"), offers) == "application/json" {
+		_ = ctx.JSON(code, map[string]string{"error": message})
+		return
+	}
+	_ = ctx.String(code, "%s", message)
 }
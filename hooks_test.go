@@ -1,9 +1,15 @@
 package cosan
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -50,6 +56,41 @@ func TestRouterHooks_BeforeRequestError(t *testing.T) {
 	}
 }
 
+func TestRouterHooks_BeforeRequestHTTPError(t *testing.T) {
+	r := New()
+	var status int
+	handlerCalled := false
+
+	r.BeforeRequest(func(req *http.Request) error {
+		if req.Header.Get("X-Forwarded-For") == "10.0.0.66" {
+			return NewHTTPError(http.StatusForbidden, "Forbidden")
+		}
+		return nil
+	})
+	r.AfterResponse(func(req *http.Request, statusCode int) {
+		status = statusCode
+	})
+	r.GET("/test", func(ctx Context) error {
+		handlerCalled = true
+		return ctx.String(200, "OK")
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Forwarded-For", "10.0.0.66")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if handlerCalled {
+		t.Error("Expected the handler not to be called")
+	}
+	if w.Code != http.StatusForbidden || w.Body.String() != "Forbidden" {
+		t.Errorf("Expected 403 Forbidden, got %d %q", w.Code, w.Body.String())
+	}
+	if status != http.StatusForbidden {
+		t.Errorf("Expected after-response hook to see 403, got %d", status)
+	}
+}
+
 func TestRouterHooks_MultipleBeforeHooks(t *testing.T) {
 	r := New()
 	var order []int
@@ -126,6 +167,75 @@ func TestRouterHooks_MultipleAfterHooks(t *testing.T) {
 	}
 }
 
+func TestRouterHooks_AfterResponsePanic(t *testing.T) {
+	var logBuf bytes.Buffer
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(os.Stderr)
+
+	r := New()
+	var status int
+	r.AfterResponse(func(req *http.Request, statusCode int) {
+		panic("metrics exporter down")
+	})
+	r.AfterResponse(func(req *http.Request, statusCode int) {
+		status = statusCode
+	})
+	r.GET("/test", func(ctx Context) error {
+		return ctx.String(201, "created")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+	if w.Code != 201 || w.Body.String() != "created" {
+		t.Errorf("Expected completed 201 response, got %d %q", w.Code, w.Body.String())
+	}
+	if status != 201 {
+		t.Errorf("Expected the next hook to run with status 201, got %d", status)
+	}
+	if !strings.Contains(logBuf.String(), "panic in after-response hook for GET /test: metrics exporter down") {
+		t.Errorf("Expected the panic to be logged, got %q", logBuf.String())
+	}
+}
+
+func TestRouterHooks_OnError(t *testing.T) {
+	r := New()
+	handlerErr := errors.New("db unavailable")
+	var observed []string
+
+	r.OnError(func(ctx Context, err error) {
+		observed = append(observed, fmt.Sprintf("first %s %v", ctx.RoutePattern(), err))
+	})
+	r.Group("/api").OnError(func(ctx Context, err error) {
+		if ctx.BytesWritten() != 0 {
+			t.Error("Expected observers to run before the response is rendered")
+		}
+		observed = append(observed, fmt.Sprintf("second %s %v", ctx.RoutePattern(), errors.Is(err, handlerErr)))
+	})
+	r.SetErrorHandler(func(ctx Context, err error) {
+		observed = append(observed, "handler")
+		_ = ctx.String(503, "unavailable")
+	})
+	r.GET("/users/:id", func(ctx Context) error {
+		return handlerErr
+	})
+	r.GET("/ok", func(ctx Context) error {
+		return ctx.String(200, "OK")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/1", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ok", nil))
+
+	want := "first /users/:id db unavailable|second /users/:id true|handler"
+	if got := strings.Join(observed, "|"); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if w.Code != 503 {
+		t.Errorf("Expected the error handler to render 503, got %d", w.Code)
+	}
+}
+
 func TestRouterHooks_CustomErrorHandler(t *testing.T) {
 	r := New()
 	called := false
@@ -157,6 +267,85 @@ func TestRouterHooks_CustomErrorHandler(t *testing.T) {
 	}
 }
 
+func TestRouterHooks_GroupErrorHandler(t *testing.T) {
+	r := New()
+	r.SetErrorHandler(func(ctx Context, err error) {
+		ctx.String(500, "router")
+	})
+
+	failing := func(ctx Context) error {
+		return errors.New("test error")
+	}
+
+	api := r.Group("/api")
+	api.GET("/item", failing)
+	api.SetErrorHandler(func(ctx Context, err error) {
+		ctx.String(422, "api")
+	})
+	v1 := api.Group("/v1")
+	v1.GET("/item", failing)
+	r.GET("/page", failing)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/api/item", 422, "api"},
+		{"/api/v1/item", 422, "api"},
+		{"/page", 500, "router"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantStatus || w.Body.String() != tt.wantBody {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.path, tt.wantStatus, tt.wantBody, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestRouterHooks_ErrorHandlerSeesRoute(t *testing.T) {
+	r := New()
+	var pattern, name string
+	var status int
+	r.SetErrorHandler(func(ctx Context, err error) {
+		pattern, name, status = ctx.RoutePattern(), ctx.RouteName(), ErrorStatus(err)
+		ctx.String(status, err.Error())
+	})
+	r.GET("/users/:id", func(ctx Context) error {
+		return NewHTTPError(http.StatusConflict, "conflict")
+	}, WithName("get-user"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/7", nil))
+
+	if pattern != "/users/:id" || name != "get-user" {
+		t.Errorf("Expected route /users/:id named get-user, got %q %q", pattern, name)
+	}
+	if status != http.StatusConflict || w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 from ErrorStatus, got %d (response %d)", status, w.Code)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{NewHTTPError(404, "missing"), 404},
+		{fmt.Errorf("wrapped: %w", NewHTTPError(403, "forbidden")), 403},
+		{errors.New("boom"), 500},
+	}
+	for _, tt := range tests {
+		if got := ErrorStatus(tt.err); got != tt.want {
+			t.Errorf("ErrorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
 func TestRouterHooks_DefaultErrorHandler(t *testing.T) {
 	r := New()
 	testErr := errors.New("test error")
@@ -177,6 +366,45 @@ func TestRouterHooks_DefaultErrorHandler(t *testing.T) {
 	}
 }
 
+func TestRouterHooks_DefaultErrorHandlerNegotiates(t *testing.T) {
+	r := New()
+	r.GET("/missing", func(ctx Context) error {
+		return NewHTTPError(404, "user 100% gone")
+	})
+	r.GET("/broken", func(ctx Context) error {
+		return errors.New("boom")
+	})
+
+	tests := []struct {
+		path        string
+		accept      string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"/missing", "application/json", 404, "application/json", "{\"error\":\"user 100% gone\"}\n"},
+		{"/broken", "application/json, text/plain;q=0.5", 500, "application/json", "{\"error\":\"Internal Server Error: boom\"}\n"},
+		{"/missing", "", 404, "text/plain; charset=utf-8", "user 100% gone"},
+		{"/missing", "*/*", 404, "text/plain; charset=utf-8", "user 100% gone"},
+		{"/missing", "text/html,application/xhtml+xml,*/*;q=0.8", 404, "text/plain; charset=utf-8", "user 100% gone"},
+		{"/broken", "text/plain, application/json", 500, "text/plain; charset=utf-8", "Internal Server Error: boom"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.code || w.Header().Get("Content-Type") != tt.contentType || w.Body.String() != tt.body {
+			t.Errorf("%s (Accept %q): expected %d %s %q, got %d %s %q", tt.path, tt.accept,
+				tt.code, tt.contentType, tt.body, w.Code, w.Header().Get("Content-Type"), w.Body.String())
+		}
+	}
+}
+
 func TestRouterHooks_NoHooks(t *testing.T) {
 	r := New()
 
@@ -192,3 +420,43 @@ func TestRouterHooks_NoHooks(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
 }
+
+func TestRouterHooks_ErrorAfterCommittedResponse(t *testing.T) {
+	r := New()
+	handlerCalled := false
+	r.SetErrorHandler(func(ctx Context, err error) {
+		handlerCalled = true
+		_ = ctx.String(500, "error")
+	})
+	r.GET("/partial", func(ctx Context) error {
+		ctx.Status(200)
+		_, _ = ctx.Write([]byte("partial"))
+		return errors.New("failed mid-stream")
+	})
+
+	var serverLog bytes.Buffer
+	srv := httptest.NewUnstartedServer(r)
+	srv.Config.ErrorLog = log.New(&serverLog, "", 0)
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/partial")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("Expected committed status 200 to be kept, got %d", resp.StatusCode)
+	}
+	if string(body) != "partial" {
+		t.Errorf("Expected body to be left intact, got %q", body)
+	}
+	if handlerCalled {
+		t.Error("Error handler should not render after the response is committed")
+	}
+	if strings.Contains(serverLog.String(), "superfluous") {
+		t.Errorf("Unexpected superfluous WriteHeader warning: %s", serverLog.String())
+	}
+}
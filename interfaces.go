@@ -31,6 +31,23 @@
 //
 //	log.Fatal(router.Listen(":8080"))
 //
+// # Route Patterns
+//
+// Patterns combine static segments, named parameters, and a trailing
+// wildcard:
+//
+//	/users/:id           // :id matches one segment
+//	/files/*path         // *path matches the rest of the path
+//	/posts/:id/:slug?    // :slug? is optional
+//
+// Only the last parameter may be optional: /posts/:id/:slug? matches both
+// /posts/10 and /posts/10/my-title, and ctx.Param("slug") is empty when
+// the segment is absent.
+//
+// A wildcard matches /files/ with an empty capture and /files/a/b with
+// "a/b". The bare prefix /files matches only when WithWildcardEmptyMatch
+// is enabled.
+//
 // # Optional Ecosystem Integrations
 //
 // Cosan can integrate with Toutā ecosystem components:
@@ -45,7 +62,14 @@
 package cosan
 
 import (
+	"bufio"
+	stdcontext "context"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"net/url"
 )
 
 // HandlerFunc defines the signature for HTTP request handlers.
@@ -68,7 +92,10 @@ import (
 type HandlerFunc func(Context) error
 
 // RequestHook is a function that runs before request processing.
-// Hooks can return errors to abort the request early.
+// Hooks can return errors to abort the request early: the route handler
+// is skipped and the error goes to the error handler, so returning an
+// *HTTPError (e.g. NewHTTPError(403, "Forbidden")) responds with its
+// status, while other errors produce 500.
 type RequestHook func(req *http.Request) error
 
 // ResponseHook is a function that runs after response is written.
@@ -77,6 +104,8 @@ type ResponseHook func(req *http.Request, statusCode int)
 
 // ErrorHandler is a custom error handling function for the router.
 // It receives the context and error, allowing custom error responses.
+// ctx.RoutePattern and ctx.RouteName identify the route that failed, and
+// ErrorStatus gives the status the error asks for.
 type ErrorHandler func(ctx Context, err error)
 
 // Router defines the interface for HTTP routing and server management.
@@ -91,39 +120,66 @@ type ErrorHandler func(ctx Context, err error)
 //	router := cosan.New()
 //	router.GET("/users", ListUsers)
 //	router.POST("/users", CreateUser)
-//	router.GET("/users/:id", GetUser)
+//	router.GET("/users/:id", GetUser, cosan.WithName("get-user"))
 //	router.Use(LoggingMiddleware, RecoveryMiddleware)
 //	router.Listen(":8080")
 type Router interface {
 	// GET registers a handler for GET requests matching the pattern.
-	GET(pattern string, handler HandlerFunc)
+	// Optional RouteOptions attach metadata such as WithName or Deprecated.
+	GET(pattern string, handler HandlerFunc, opts ...RouteOption)
 
 	// POST registers a handler for POST requests matching the pattern.
-	POST(pattern string, handler HandlerFunc)
+	POST(pattern string, handler HandlerFunc, opts ...RouteOption)
 
 	// PUT registers a handler for PUT requests matching the pattern.
-	PUT(pattern string, handler HandlerFunc)
+	PUT(pattern string, handler HandlerFunc, opts ...RouteOption)
 
 	// DELETE registers a handler for DELETE requests matching the pattern.
-	DELETE(pattern string, handler HandlerFunc)
+	DELETE(pattern string, handler HandlerFunc, opts ...RouteOption)
 
 	// PATCH registers a handler for PATCH requests matching the pattern.
-	PATCH(pattern string, handler HandlerFunc)
+	PATCH(pattern string, handler HandlerFunc, opts ...RouteOption)
 
 	// OPTIONS registers a handler for OPTIONS requests matching the pattern.
-	OPTIONS(pattern string, handler HandlerFunc)
+	OPTIONS(pattern string, handler HandlerFunc, opts ...RouteOption)
 
-	// HEAD registers a handler for HEAD requests matching the pattern.
-	HEAD(pattern string, handler HandlerFunc)
+	// HEAD registers a handler for HEAD requests matching the pattern. Without
+	// one, HEAD requests are served by the GET route with the body discarded.
+	HEAD(pattern string, handler HandlerFunc, opts ...RouteOption)
 
 	// Use registers middleware to be applied to all routes.
 	// Middleware is executed in the order registered (outer to inner).
 	Use(middleware ...Middleware)
 
+	// UsePrepend registers middleware ahead of the middleware already
+	// registered, so it runs first (outermost).
+	UsePrepend(middleware ...Middleware)
+
 	// Group creates a route group with the given prefix.
 	// Groups support scoped middleware and nested grouping.
+	// Patterns registered on a group are joined to the prefix with exactly
+	// one slash; an empty or "/" pattern maps to the group prefix itself.
 	Group(prefix string) Router
 
+	// Version creates a group prefixed with /version whose routes carry
+	// the version in their metadata, e.g. Version("v1") serves /v1/...
+	Version(version string) Router
+
+	// Route returns a builder that registers handlers for several methods
+	// on one pattern, with optional middleware scoped to them:
+	//
+	//	router.Route("/users/:id").GET(GetUser).PUT(UpdateUser)
+	Route(pattern string) RouteBuilder
+
+	// Resource registers the conventional REST routes (Index, Create,
+	// Show, Update, Destroy) for the actions controller implements.
+	Resource(name string, controller ResourceController)
+
+	// RegisterRoutes registers a table of routes, each with optional name,
+	// tags, and middleware. Entries that fail are skipped and reported
+	// together in the returned error instead of panicking.
+	RegisterRoutes(defs []RouteDef) error
+
 	// ServeHTTP implements http.Handler interface.
 	// This allows the router to be used with the standard library:
 	//   http.ListenAndServe(":8080", router)
@@ -134,25 +190,115 @@ type Router interface {
 	//   http.ListenAndServe(addr, router)
 	Listen(addr string) error
 
+	// ListenTLS starts an HTTPS server on the specified address, serving
+	// HTTP/2 to clients that negotiate it through ALPN.
+	ListenTLS(addr, certFile, keyFile string) error
+
+	// Serve serves HTTP on a caller-provided listener, such as an
+	// ephemeral port or a socket-activated file descriptor.
+	Serve(l net.Listener) error
+
+	// Shutdown gracefully stops the servers started by Listen, ListenTLS,
+	// and Serve, waiting for active requests until ctx is done.
+	Shutdown(ctx stdcontext.Context) error
+
 	// BeforeRequest registers a hook to run before each request.
-	// Hooks execute in registration order and can return errors to abort.
+	// Hooks execute in registration order and can return errors to abort;
+	// an *HTTPError sets the response status.
 	BeforeRequest(hook RequestHook)
 
 	// AfterResponse registers a hook to run after each response.
 	// Hooks execute in registration order and cannot abort requests.
+	// With recovery enabled (the default), a panicking hook is logged and
+	// the remaining hooks still run.
 	AfterResponse(hook ResponseHook)
 
+	// OnError registers an observer that runs for every error before the
+	// error handler renders the response, e.g. to report to an alerting
+	// service. Unlike SetErrorHandler, any number of observers can be
+	// registered; they run in registration order and must not write the
+	// response.
+	OnError(observer ErrorHandler)
+
 	// SetErrorHandler sets a custom error handler for the router.
-	// If not set, a default error handler is used.
+	// If not set, the default handler responds with an *HTTPError's status
+	// and message (500 for other errors), as JSON {"error": message} when
+	// the Accept header prefers JSON and as plain text otherwise.
+	// Called on a group, the handler applies only to routes registered
+	// through that group (and its nested groups).
 	SetErrorHandler(handler ErrorHandler)
 
+	// Fallback sets a handler for every request that matches no route,
+	// replacing the 404 and 405 responses. Unlike SetNotFoundHandler's
+	// role as a custom 404 page, it is meant for catch-all patterns such as
+	// forwarding to an upstream; explicit routes take precedence.
+	Fallback(handler HandlerFunc)
+
+	// SetNotFoundHandler sets the handler for requests that match no route.
+	// If not set, a plain-text 404 is returned (see also WithNotFoundJSON).
+	SetNotFoundHandler(handler HandlerFunc)
+
+	// NotFound sets the handler for requests that match no route. On a
+	// group it only applies to unmatched paths under the group's prefix,
+	// resolved by the longest matching group prefix, so an API group can
+	// answer with JSON while other paths keep the global behavior.
+	NotFound(handler HandlerFunc)
+
+	// Health registers a GET readiness endpoint that responds 200 when all
+	// checks pass and 503 with a JSON report of each check otherwise, or
+	// while the WithReadiness function has not yet passed.
+	Health(path string, checks ...HealthCheck)
+
+	// Liveness registers a GET endpoint that always responds 200 while the
+	// process is up.
+	Liveness(path string)
+
+	// Static serves the files under root at prefix, honoring Range and
+	// conditional requests. Paths cannot escape root. With
+	// WithPrecompressedStatic, .br and .gz variants are served to clients
+	// that accept them.
+	Static(prefix, root string)
+
+	// SPA serves a single-page application from root at prefix: existing
+	// files are served as-is, extensionless paths fall back to indexFile
+	// for client-side routing, and missing assets respond 404.
+	SPA(prefix, root, indexFile string)
+
+	// Mount serves every request under prefix, for any common method, with
+	// an http.Handler. The prefix is stripped from the path the handler
+	// sees unless KeepMountPrefix is given.
+	Mount(prefix string, handler http.Handler, opts ...MountOption)
+
+	// Walk calls fn for every registered route in a deterministic order
+	// (by method, then matcher lookup order), stopping at the first error.
+	// Unlike GetRoutes, it exposes the registered handler.
+	Walk(fn WalkFunc) error
+
 	// GetRoutes returns all registered routes with metadata for introspection.
 	// Useful for documentation generation and route inspection.
 	GetRoutes() []RouteInfo
 
+	// GetRoutesSorted returns the routes ordered by method, then pattern.
+	// GetRoutes keeps registration order.
+	GetRoutesSorted() []RouteInfo
+
+	// DumpRoutes writes a method/pattern/name table of the routes to w,
+	// in GetRoutesSorted order.
+	DumpRoutes(w io.Writer) error
+
+	// Reset removes every route and clears the compiled state so routes and
+	// middleware can be registered again, for test harnesses and hot
+	// reloads. Requires WithDynamicRoutes; not for use on the request path.
+	Reset() error
+
 	// FindRoute finds a route by name from its metadata.
 	// Returns nil if no route with the given name exists.
 	FindRoute(name string) *RouteInfo
+
+	// MethodsFor returns the sorted HTTP methods with a route matching the
+	// concrete path, e.g. for OPTIONS responses or hypermedia links.
+	// Returns nil if no route matches.
+	MethodsFor(path string) []string
 }
 
 // Route represents a registered HTTP route.
@@ -179,10 +325,22 @@ type Route interface {
 type ParamReader interface {
 	// Param returns the value of the named path parameter.
 	// Returns empty string if parameter doesn't exist.
+	//
+	// Values are percent-decoded: /users/John%20Doe yields "John Doe".
+	// An encoded slash stays within its segment, so /files/a%2Fb matches
+	// /files/:name with name "a/b" rather than a two-segment route.
+	// Only encoded slashes and percent signs stay encoded during matching;
+	// other escapes are decoded, so static segments match however the
+	// client encoded them.
 	Param(key string) string
 
 	// Params returns all path parameters as a map.
 	Params() map[string]string
+
+	// BindParams maps path parameters into struct fields tagged
+	// `param:"id"`, using the same conversion rules as BindQuery.
+	// Returns a *BindError naming the field if a conversion fails.
+	BindParams(v interface{}) error
 }
 
 // QueryReader provides access to URL query parameters.
@@ -193,6 +351,7 @@ type ParamReader interface {
 //	// For URL "?name=John&tag=go&tag=web"
 //	name := ctx.Query("name")           // "John"
 //	tags := ctx.QueryAll("tag")         // []string{"go", "web"}
+//	page := ctx.QueryIntDefault("page", 1)
 type QueryReader interface {
 	// Query returns the first value of the named query parameter.
 	// Returns empty string if parameter doesn't exist.
@@ -201,6 +360,35 @@ type QueryReader interface {
 	// QueryAll returns all values of the named query parameter.
 	// Returns empty slice if parameter doesn't exist.
 	QueryAll(key string) []string
+
+	// QueryDefault returns the named query parameter, or def if it is
+	// missing or empty.
+	QueryDefault(key, def string) string
+
+	// QueryInt returns the named query parameter parsed as an int.
+	// Returns an error if the parameter is missing or not an integer.
+	QueryInt(key string) (int, error)
+
+	// QueryIntDefault returns the named query parameter parsed as an int,
+	// or def if it is missing, empty, or not an integer.
+	QueryIntDefault(key string, def int) int
+
+	// QueryBool returns the named query parameter parsed as a bool
+	// ("1", "t", "true", ...). Returns false if missing or invalid.
+	QueryBool(key string) bool
+
+	// QueryString returns the raw, still-encoded query string without the
+	// leading "?", e.g. for proxying or request signing.
+	QueryString() string
+
+	// QueryValues returns all parsed query parameters. The map is cached
+	// for the request and shared with Query; callers must not modify it.
+	QueryValues() url.Values
+
+	// BindQuery maps query parameters into struct fields tagged `query:"name"`.
+	// Values are converted to the field type; slices receive every value.
+	// Returns a *BindError naming the field if a conversion fails.
+	BindQuery(v interface{}) error
 }
 
 // BodyReader provides access to request body content.
@@ -214,12 +402,20 @@ type QueryReader interface {
 //	}
 type BodyReader interface {
 	// Bind parses the request body into the provided struct.
-	// Automatically detects Content-Type (JSON, XML, form).
-	// Returns error if parsing fails.
+	// Automatically detects Content-Type: JSON by default, and
+	// application/x-protobuf or application/msgpack when a codec is set
+	// with WithProtoCodec or WithMsgPackCodec.
+	// Returns error if parsing fails, or an *HTTPError 413 if the body
+	// exceeds the WithMaxBindBytes limit (1 MiB by default).
 	Bind(v interface{}) error
 
+	// BindStrict parses the JSON request body like Bind, but returns an
+	// error naming any field not present in the target struct.
+	BindStrict(v interface{}) error
+
 	// BodyBytes returns the raw request body as bytes.
-	// Body can only be read once unless cached.
+	// The body is cached on first read, so BodyBytes and Bind can be called
+	// in any order, e.g. a middleware hashing the body before binding.
 	BodyBytes() ([]byte, error)
 }
 
@@ -235,12 +431,51 @@ type ResponseWriter interface {
 	// JSON writes a JSON response with the given status code.
 	JSON(code int, v interface{}) error
 
+	// OK writes v as a JSON response with status 200.
+	OK(v interface{}) error
+
+	// StreamArray writes the values received from items as a JSON array,
+	// encoding each as it arrives, and closes the array when items is
+	// closed. An empty stream writes [].
+	StreamArray(code int, items <-chan interface{}) error
+
 	// String writes a formatted string response with the given status code.
 	String(code int, format string, args ...interface{}) error
 
+	// Text writes a formatted string response with status 200.
+	Text(format string, args ...interface{}) error
+
 	// HTML writes an HTML response with the given status code.
 	HTML(code int, html string) error
 
+	// Blob writes pre-serialized bytes (images, PDFs, cached payloads)
+	// with an explicit content type and Content-Length.
+	Blob(code int, contentType string, data []byte) error
+
+	// File streams the named file, honoring Range and conditional
+	// requests (206 Partial Content, 304 Not Modified). A missing file
+	// yields an *HTTPError 404. With WithPrecompressedStatic, a .br or .gz
+	// variant beside the file is served to clients that accept it.
+	File(name string) error
+
+	// Protobuf writes msg as application/x-protobuf using the codec set
+	// with WithProtoCodec. Returns ErrNoProtoCodec if none is configured.
+	Protobuf(code int, msg interface{}) error
+
+	// MsgPack writes v as application/msgpack using the codec set with
+	// WithMsgPackCodec. Returns ErrNoMsgPackCodec if none is configured.
+	MsgPack(code int, v interface{}) error
+
+	// Negotiate writes v as JSON, MessagePack, or Protocol Buffers,
+	// whichever the Accept header prefers among the configured codecs.
+	// Responds with an *HTTPError 406 when none is acceptable.
+	Negotiate(code int, v interface{}) error
+
+	// Problem writes an RFC 7807 application/problem+json response.
+	// An empty title defaults to the status text; the instance is the
+	// request path.
+	Problem(status int, title, detail string) error
+
 	// Status sets the HTTP status code.
 	// Must be called before writing response body.
 	Status(code int)
@@ -251,6 +486,24 @@ type ResponseWriter interface {
 	// Write writes the response body bytes.
 	// Implements io.Writer interface.
 	Write([]byte) (int, error)
+
+	// Flush sends any buffered response data to the client, through any
+	// writers wrapped by middleware. It returns http.ErrNotSupported when
+	// the underlying writer cannot flush.
+	Flush() error
+
+	// Hijack takes over the underlying connection, e.g. for a WebSocket
+	// upgrade. It returns http.ErrNotSupported when the underlying writer
+	// cannot be hijacked.
+	Hijack() (net.Conn, *bufio.ReadWriter, error)
+
+	// BytesWritten returns the number of response body bytes written so far.
+	// Behind a compressing middleware this is the compressed size.
+	BytesWritten() int64
+
+	// StatusCode returns the response status written so far, or 200 if no
+	// header has been written yet.
+	StatusCode() int
 }
 
 // Context represents the context of an HTTP request/response cycle.
@@ -288,12 +541,122 @@ type Context interface {
 	// Useful for low-level response manipulation.
 	Response() http.ResponseWriter
 
+	// IsCanceled reports whether the request's context is done, so long
+	// running handlers can stop once the client has gone away.
+	IsCanceled() bool
+
+	// Err returns the request context's error, or nil while it is live.
+	Err() error
+
+	// BindHeader maps request headers into struct fields tagged
+	// `header:"X-Tenant-ID"`, using the same conversion rules as BindQuery.
+	// Header names are canonicalized, so tag case does not matter.
+	BindHeader(v interface{}) error
+
+	// BindAll populates a struct from every request source, so a single
+	// struct can capture the whole request. Sources are applied from least
+	// to most specific, each overriding the previous:
+	//
+	//	body (json) < headers (`header`) < query (`query`) < path (`param`)
+	//
+	// A missing or empty body is not an error.
+	BindAll(v interface{}) error
+
+	// MultipartForm parses a multipart/form-data body, holding up to the
+	// WithMaxMultipartMemory limit in memory and spilling files beyond it
+	// to disk.
+	MultipartForm() (*multipart.Form, error)
+
+	// FormFile returns the first file uploaded in the named form field,
+	// or http.ErrMissingFile.
+	FormFile(name string) (*multipart.FileHeader, error)
+
+	// FormValue returns the first value for the named field, from the
+	// request body or the query string, with body values first. The form
+	// is parsed once per request; parse errors yield "".
+	FormValue(key string) string
+
+	// PostForm returns the first value for the named field of a
+	// url-encoded or multipart body, ignoring the query string.
+	PostForm(key string) string
+
+	// ParseForm parses the form FormValue and PostForm read and returns
+	// the parse error, such as a malformed or oversized body, which they
+	// ignore. The error is cached, so it may be checked after reading
+	// values.
+	ParseForm() error
+
+	// Validate checks v using the Validator configured with WithValidator.
+	// Returns ErrNoValidator if no Validator is configured.
+	Validate(v interface{}) error
+
+	// Cookie returns the named request cookie, or http.ErrNoCookie.
+	Cookie(name string) (*http.Cookie, error)
+
+	// SetCookie adds a Set-Cookie header to the response. Unless changed
+	// with WithCookieDefaults, cookies are HttpOnly and SameSite=Lax, and
+	// Secure on TLS requests.
+	SetCookie(cookie *http.Cookie)
+
+	// SetCookieRaw adds a Set-Cookie header for cookie as given, without
+	// the defaults SetCookie applies, e.g. for a CSRF cookie that must not
+	// be HttpOnly.
+	SetCookieRaw(cookie *http.Cookie)
+
+	// SetSignedCookie sets an HMAC-SHA256 signed cookie, readable with
+	// SignedCookie using the same secret. The value is signed, not encrypted.
+	SetSignedCookie(name, value string, secret []byte)
+
+	// SignedCookie returns the verified value of a cookie set with
+	// SetSignedCookie. Tampered cookies yield ErrInvalidCookieSignature.
+	SignedCookie(name string, secret []byte) (string, error)
+
+	// SetResponse replaces the underlying http.ResponseWriter.
+	// Middleware uses this to wrap the writer (e.g. for compression);
+	// all subsequent response methods write through the new writer.
+	SetResponse(w http.ResponseWriter)
+
+	// RoutePattern returns the pattern of the matched route (e.g.
+	// "/users/:id"), or "" when no route matched. It is available to
+	// middleware, handlers, and error handlers.
+	RoutePattern() string
+
+	// RouteName returns the name given to the matched route with WithName,
+	// or "" if it has none.
+	RouteName() string
+
+	// RouteTags returns the tags given to the matched route with WithTags,
+	// or nil if it has none. Like RoutePattern it is set before any
+	// middleware runs, so middleware can act on it (e.g. authorization by
+	// tag). The slice is shared with the route and must not be modified.
+	RouteTags() []string
+
+	// WithRequest replaces the request seen by Request and every downstream
+	// handler, e.g. to override the method or attach values with
+	// req.WithContext. The context is mutated in place rather than copied,
+	// so the change is visible to the rest of the chain but not to
+	// middleware that already returned. Routing is not repeated: path
+	// parameters still come from the matched route. Cached query values,
+	// body, and logger are discarded and rebuilt from the new request.
+	WithRequest(req *http.Request)
+
+	// Logger returns a structured logger for the request, derived from the
+	// logger set with WithLogger (default slog.Default()) and carrying the
+	// method, path, route pattern, and request ID.
+	Logger() *slog.Logger
+
 	// Set stores a value in the context for the request lifetime.
 	Set(key string, value interface{})
 
 	// Get retrieves a value from the context.
 	// Returns nil if key doesn't exist.
 	Get(key string) interface{}
+
+	// MustGet retrieves a value from the context, panicking with
+	// `cosan: key "claims" not found in context` if key is absent.
+	// Use it only when upstream middleware guarantees the value is set,
+	// so a missing key is a programming error rather than bad input.
+	MustGet(key string) interface{}
 }
 
 // Matcher defines the interface for route matching strategies.
@@ -310,12 +673,16 @@ type Context interface {
 type Matcher interface {
 	// Match finds a route matching the method and path.
 	// Returns the route, extracted parameters, and whether a match was found.
-	Match(method, path string) (*Route, map[string]string, bool)
+	Match(method, path string) (Route, map[string]string, bool)
 
 	// Register adds a route to the matcher.
 	// Must be called before Compile().
 	Register(method, pattern string, handler HandlerFunc) error
 
+	// Routes returns all routes held by the matcher in registration order.
+	// The matcher is the source of truth for route introspection.
+	Routes() []Route
+
 	// Compile optimizes the route tree for matching.
 	// Must be called before Match() and after all routes are registered.
 	// Route registration is not allowed after compilation.
@@ -401,6 +768,90 @@ type Binder interface {
 	Bind(src interface{}, dst interface{}) error
 }
 
+// Validator defines the interface for struct validation.
+// This is an optional integration for components like toutago-datamapper
+// or go-playground/validator.
+//
+// When a Validator is configured, ctx.Validate delegates to it:
+//
+//	router := cosan.New(cosan.WithValidator(datamapper.NewValidator()))
+//
+//	router.POST("/users", func(ctx cosan.Context) error {
+//	    var user User // fields tagged `validate:"required,email"`
+//	    if err := ctx.Bind(&user); err != nil {
+//	        return err
+//	    }
+//	    if err := ctx.Validate(&user); err != nil {
+//	        return ctx.JSON(400, map[string]string{"error": err.Error()})
+//	    }
+//	    return ctx.JSON(201, user)
+//	})
+type Validator interface {
+	// Validate checks the value and returns an error describing any violations.
+	Validate(v interface{}) error
+}
+
+// JSONMarshaler defines a pluggable JSON codec, so a faster library such as
+// json-iterator, goccy/go-json, or segmentio/encoding can replace
+// encoding/json without adding a dependency to Cosan itself.
+//
+// When a codec is configured, ctx.JSON and ctx.Bind use it:
+//
+//	router := cosan.New(cosan.WithJSONCodec(myCodec{}))
+//
+// Without one, encoding/json is used.
+type JSONMarshaler interface {
+	// Marshal returns the JSON encoding of v.
+	Marshal(v interface{}) ([]byte, error)
+
+	// NewDecoder returns a decoder reading JSON from r.
+	// For strict binding (BindStrict, WithStrictJSON) the decoder must also
+	// implement DisallowUnknownFields(), as json.Decoder does.
+	NewDecoder(r io.Reader) Decoder
+}
+
+// ProtoMarshaler defines a pluggable Protocol Buffers codec, keeping the
+// core free of a protobuf dependency. An implementation backed by
+// google.golang.org/protobuf type-asserts to proto.Message:
+//
+//	type protoCodec struct{}
+//
+//	func (protoCodec) Marshal(v interface{}) ([]byte, error) {
+//	    return proto.Marshal(v.(proto.Message))
+//	}
+//
+//	func (protoCodec) Unmarshal(data []byte, v interface{}) error {
+//	    return proto.Unmarshal(data, v.(proto.Message))
+//	}
+//
+//	router := cosan.New(cosan.WithProtoCodec(protoCodec{}))
+type ProtoMarshaler interface {
+	// Marshal returns the wire encoding of the message.
+	Marshal(msg interface{}) ([]byte, error)
+
+	// Unmarshal parses the wire encoding into the message.
+	Unmarshal(data []byte, msg interface{}) error
+}
+
+// MsgPackMarshaler defines a pluggable MessagePack codec, keeping the core
+// free of a msgpack dependency (e.g. wrap vmihailenco/msgpack or
+// shamaton/msgpack):
+//
+//	router := cosan.New(cosan.WithMsgPackCodec(msgpackCodec{}))
+type MsgPackMarshaler interface {
+	// Marshal returns the MessagePack encoding of v.
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal parses MessagePack data into v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Decoder decodes values from a request body.
+type Decoder interface {
+	// Decode reads the next encoded value into v.
+	Decode(v interface{}) error
+}
+
 // Renderer defines the interface for template rendering.
 // This is an optional integration for components like toutago-fith-renderer.
 //
@@ -0,0 +1,46 @@
+package cosan
+
+import "log/slog"
+
+// RequestIDKey is the context key under which the request ID is stored
+// (see middleware.RequestID). Context.Logger includes it when present.
+const RequestIDKey = "requestID"
+
+// WithLogger sets the base structured logger from which Context.Logger
+// derives each request's logger. Defaults to slog.Default().
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithLogger(slog.New(slog.NewJSONHandler(os.Stdout, nil))))
+func WithLogger(logger *slog.Logger) Option {
+	return func(r *router) {
+		r.logger = logger
+	}
+}
+
+// Logger returns the request-scoped logger. It is derived from the router's
+// logger on first use, with the method, path, matched route pattern, and
+// request ID (when set) attached, so it should be requested after any
+// middleware that assigns the request ID.
+func (c *context) Logger() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+
+	base := slog.Default()
+	if c.router != nil && c.router.logger != nil {
+		base = c.router.logger
+	}
+
+	attrs := make([]any, 0, 8)
+	attrs = append(attrs, "method", c.req.Method, "path", c.req.URL.Path)
+	if c.route != nil {
+		attrs = append(attrs, "route", c.route.pattern)
+	}
+	if id, ok := c.values[RequestIDKey].(string); ok && id != "" {
+		attrs = append(attrs, "request_id", id)
+	}
+
+	c.logger = base.With(attrs...)
+	return c.logger
+}
@@ -0,0 +1,54 @@
+package cosan
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.Set(RequestIDKey, "req-42")
+			return next(ctx)
+		}
+	}))
+	r.GET("/users/:id", func(ctx Context) error {
+		ctx.Logger().Info("loaded user", "id", ctx.Param("id"))
+		return nil
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/7", nil))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Invalid log entry %q: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"msg":        "loaded user",
+		"method":     "GET",
+		"path":       "/users/7",
+		"route":      "/users/:id",
+		"request_id": "req-42",
+		"id":         "7",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+}
+
+func TestContext_LoggerDefault(t *testing.T) {
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	if ctx.Logger() == nil {
+		t.Fatal("Expected a logger without WithLogger")
+	}
+	if ctx.Logger() != ctx.Logger() {
+		t.Error("Expected the request logger to be derived once")
+	}
+}
@@ -2,6 +2,7 @@ package cosan
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -10,9 +11,35 @@ import (
 type simpleMatcher struct {
 	routes   map[string]*route // key: "METHOD:PATH"
 	compiled bool
+	dynamic  bool
+	count    int // Registration counter for ordering Routes()
 	mu       sync.RWMutex
 }
 
+// routeInserter is implemented by the built-in matchers so the router can
+// store its own route (with metadata) instead of the matcher creating a copy.
+type routeInserter interface {
+	insert(rt *route) error
+}
+
+// dynamicMatcher is implemented by matchers that can accept registrations
+// after Compile (see WithDynamicRoutes).
+type dynamicMatcher interface {
+	setDynamic(enabled bool)
+}
+
+// wildcardEmptyMatcher is implemented by matchers with wildcard routes
+// (see WithWildcardEmptyMatch).
+type wildcardEmptyMatcher interface {
+	setWildcardEmptyMatch(enabled bool)
+}
+
+// resettableMatcher is implemented by matchers that can drop every route
+// and return to their uncompiled state (see Router.Reset).
+type resettableMatcher interface {
+	reset()
+}
+
 // newSimpleMatcher creates a new simple matcher.
 func newSimpleMatcher() Matcher {
 	return &simpleMatcher{
@@ -23,44 +50,84 @@ func newSimpleMatcher() Matcher {
 
 // Register adds a route to the matcher.
 func (m *simpleMatcher) Register(method, pattern string, handler HandlerFunc) error {
+	return m.insert(&route{
+		method:  method,
+		pattern: pattern,
+		handler: handler,
+	})
+}
+
+// insert adds an existing route to the matcher.
+func (m *simpleMatcher) insert(rt *route) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.compiled {
+	if m.compiled && !m.dynamic {
 		return fmt.Errorf("matcher already compiled")
 	}
 
-	key := method + ":" + pattern
+	key := rt.method + ":" + rt.pattern
 	if _, exists := m.routes[key]; exists {
-		return fmt.Errorf("duplicate route: %s %s", method, pattern)
+		return fmt.Errorf("duplicate route: %s %s", rt.method, rt.pattern)
 	}
 
-	m.routes[key] = &route{
-		method:  method,
-		pattern: pattern,
-		handler: handler,
-	}
+	rt.seq = m.count
+	m.count++
+	m.routes[key] = rt
 
 	return nil
 }
 
+// Routes returns all registered routes in registration order.
+func (m *simpleMatcher) Routes() []Route {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	routes := make([]*route, 0, len(m.routes))
+	for _, rt := range m.routes {
+		routes = append(routes, rt)
+	}
+
+	return sortedRoutes(routes)
+}
+
+// sortedRoutes orders routes by registration and exposes them as Route values.
+func sortedRoutes(routes []*route) []Route {
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].seq < routes[j].seq
+	})
+
+	result := make([]Route, len(routes))
+	for i, rt := range routes {
+		result[i] = rt
+	}
+
+	return result
+}
+
 // Match finds a route matching the method and path.
 // For Phase 1, this only does exact matching.
-func (m *simpleMatcher) Match(method, path string) (*Route, map[string]string, bool) {
+func (m *simpleMatcher) Match(method, path string) (Route, map[string]string, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	key := method + ":" + path
 	if rt, found := m.routes[key]; found {
-		// Return the route as Route interface pointer
-		var routeInterface Route = rt
 		// No params for exact match
-		return &routeInterface, make(map[string]string), true
+		return rt, make(map[string]string), true
 	}
 
 	return nil, nil, false
 }
 
+// setDynamic allows registration after Compile.
+func (m *simpleMatcher) setDynamic(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dynamic = enabled
+}
+
 // Compile optimizes the matcher (no-op for simple matcher).
 func (m *simpleMatcher) Compile() error {
 	m.mu.Lock()
@@ -69,3 +136,13 @@ func (m *simpleMatcher) Compile() error {
 	m.compiled = true
 	return nil
 }
+
+// reset drops every route and clears the compiled state.
+func (m *simpleMatcher) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.routes = make(map[string]*route)
+	m.compiled = false
+	m.count = 0
+}
@@ -1,6 +1,8 @@
 package cosan
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -104,3 +106,106 @@ func TestSimpleMatcher_MultipleRoutes(t *testing.T) {
 		}
 	}
 }
+
+func TestSimpleMatcher_Routes(t *testing.T) {
+	m := newSimpleMatcher()
+	handler := func(ctx Context) error { return nil }
+
+	m.Register("GET", "/users", handler)
+	m.Register("POST", "/users", handler)
+	m.Register("GET", "/posts", handler)
+
+	routes := m.Routes()
+	if len(routes) != 3 {
+		t.Fatalf("Expected 3 routes, got %d", len(routes))
+	}
+	if routes[1].Method() != "POST" || routes[2].Pattern() != "/posts" {
+		t.Errorf("Routes not in registration order")
+	}
+}
+
+// customRoute and customMatcher show a third-party Matcher written only
+// against the exported interfaces.
+type customRoute struct {
+	method, pattern string
+	handler         HandlerFunc
+}
+
+func (r *customRoute) Pattern() string      { return r.pattern }
+func (r *customRoute) Method() string       { return r.method }
+func (r *customRoute) Handler() HandlerFunc { return r.handler }
+
+type customMatcher struct {
+	routes []Route
+}
+
+func (m *customMatcher) Register(method, pattern string, handler HandlerFunc) error {
+	m.routes = append(m.routes, &customRoute{method, pattern, handler})
+	return nil
+}
+
+func (m *customMatcher) Match(method, path string) (Route, map[string]string, bool) {
+	for _, r := range m.routes {
+		if r.Method() == method && r.Pattern() == path {
+			return r, nil, true
+		}
+	}
+	return nil, nil, false
+}
+
+func (m *customMatcher) Routes() []Route { return m.routes }
+func (m *customMatcher) Compile() error  { return nil }
+
+func TestCustomMatcher_WithRouter(t *testing.T) {
+	router := New(WithMatcher(&customMatcher{}))
+	router.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.Header().Set("X-Chain", "yes")
+			return next(ctx)
+		}
+	}))
+	router.GET("/custom", func(ctx Context) error {
+		return ctx.String(200, "custom")
+	}, WithName("custom"), WithTags("admin"), WithVersion("v2"))
+
+	req := httptest.NewRequest(http.MethodGet, "/custom", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Body.String() != "custom" {
+		t.Errorf("Expected 200 custom, got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Chain") != "yes" {
+		t.Error("Expected middleware to run with a custom matcher")
+	}
+	if routes := router.GetRoutes(); len(routes) != 1 || routes[0].Pattern != "/custom" || routes[0].Name != "custom" {
+		t.Errorf("Unexpected routes: %+v", routes)
+	}
+	if info := router.FindRoute("custom"); info == nil || len(info.Tags) != 1 || info.Version != "v2" {
+		t.Errorf("Expected FindRoute to return the route's metadata, got %+v", info)
+	}
+
+	var walked []string
+	_ = router.Walk(func(method, pattern string, handler HandlerFunc) error {
+		walked = append(walked, method+" "+pattern)
+		return nil
+	})
+	if len(walked) != 1 || walked[0] != "GET /custom" {
+		t.Errorf("Unexpected walk: %v", walked)
+	}
+}
+
+func TestRadixMatcher_WithRouter(t *testing.T) {
+	router := New(WithMatcher(newRadixMatcher()))
+	router.GET("/users/:id", func(ctx Context) error {
+		return ctx.String(200, ctx.Param("id"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != "42" {
+		t.Errorf("Expected 42, got %q", w.Body.String())
+	}
+}
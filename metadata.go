@@ -1,11 +1,22 @@
 package cosan
 
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
 // RouteMetadata contains metadata about a route for documentation and introspection
 type RouteMetadata struct {
 	Name        string
 	Description string
 	Tags        []string
 	Deprecated  bool
+	Sunset      time.Time
 	Version     string
 }
 
@@ -17,10 +28,15 @@ type RouteInfo struct {
 	Description string
 	Tags        []string
 	Deprecated  bool
+	Sunset      time.Time
 	Version     string
+	Consumes    []string // request media types, from Consumes
+	Produces    []string // response media types, from Produces
 }
 
-// WithName sets the name of the route for documentation
+// WithName sets the name of the route for documentation and FindRoute.
+// Names must be unique: registering a second route with the same name fails
+// like a duplicate pattern does.
 func WithName(name string) RouteOption {
 	return func(r *route) {
 		if r.metadata == nil {
@@ -60,6 +76,46 @@ func Deprecated() RouteOption {
 	}
 }
 
+// WithSunset sets the date after which a deprecated route may be removed.
+// It is emitted as the Sunset header when deprecation headers are enabled.
+func WithSunset(t time.Time) RouteOption {
+	return func(r *route) {
+		if r.metadata == nil {
+			r.metadata = &RouteMetadata{}
+		}
+		r.metadata.Sunset = t
+	}
+}
+
+// WithDeprecationHeaders enables the Deprecation and Sunset response headers
+// on routes marked Deprecated, so API consumers can detect them programmatically.
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithDeprecationHeaders(true))
+//	router.GET("/v1/users", ListUsersV1, cosan.Deprecated(), cosan.WithSunset(sunset))
+func WithDeprecationHeaders(enabled bool) Option {
+	return func(r *router) {
+		r.deprecationHeaders = enabled
+	}
+}
+
+// deprecationHandler wraps a deprecated route's handler to emit deprecation headers.
+func deprecationHandler(next HandlerFunc, sunset time.Time) HandlerFunc {
+	var sunsetValue string
+	if !sunset.IsZero() {
+		sunsetValue = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(ctx Context) error {
+		ctx.Header().Set("Deprecation", "true")
+		if sunsetValue != "" {
+			ctx.Header().Set("Sunset", sunsetValue)
+		}
+		return next(ctx)
+	}
+}
+
 // WithVersion sets the API version for the route
 func WithVersion(version string) RouteOption {
 	return func(r *route) {
@@ -73,30 +129,55 @@ func WithVersion(version string) RouteOption {
 // RouteOption is a functional option for configuring route metadata
 type RouteOption func(*route)
 
-// GetRoutes returns all registered routes with metadata for introspection
+// GetRoutes returns all registered routes with metadata for introspection,
+// in registration order. Routes come from the router itself, so metadata is
+// available whatever matcher is in use.
 func (r *router) GetRoutes() []RouteInfo {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	routes := make([]RouteInfo, 0, len(r.routes))
-	for _, route := range r.routes {
-		info := RouteInfo{
-			Method:  route.method,
-			Pattern: route.pattern,
-		}
+	for _, rt := range r.routes {
+		routes = append(routes, rt.info())
+	}
 
-		if route.metadata != nil {
-			info.Name = route.metadata.Name
-			info.Description = route.metadata.Description
-			info.Tags = route.metadata.Tags
-			info.Deprecated = route.metadata.Deprecated
-			info.Version = route.metadata.Version
+	return routes
+}
+
+// GetRoutesSorted returns the same routes as GetRoutes, ordered by method
+// and then pattern, independent of registration order. Use it for snapshot
+// tests and route listings.
+func (r *router) GetRoutesSorted() []RouteInfo {
+	routes := r.GetRoutes()
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Method != routes[j].Method {
+			return routes[i].Method < routes[j].Method
 		}
+		return routes[i].Pattern < routes[j].Pattern
+	})
 
-		routes = append(routes, info)
+	return routes
+}
+
+// DumpRoutes writes a table of the routes, in GetRoutesSorted order, to w.
+//
+// Example output:
+//
+//	METHOD  PATTERN     NAME
+//	GET     /users      users.list
+//	GET     /users/:id  users.show
+func (r *router) DumpRoutes(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATTERN\tNAME")
+	for _, info := range r.GetRoutesSorted() {
+		name := info.Name
+		if info.Deprecated {
+			name = strings.TrimSpace(name + " (deprecated)")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", info.Method, info.Pattern, name)
 	}
 
-	return routes
+	return tw.Flush()
 }
 
 // FindRoute finds a route by name
@@ -104,19 +185,48 @@ func (r *router) FindRoute(name string) *RouteInfo {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	for _, route := range r.routes {
-		if route.metadata != nil && route.metadata.Name == name {
-			return &RouteInfo{
-				Method:      route.method,
-				Pattern:     route.pattern,
-				Name:        route.metadata.Name,
-				Description: route.metadata.Description,
-				Tags:        route.metadata.Tags,
-				Deprecated:  route.metadata.Deprecated,
-				Version:     route.metadata.Version,
-			}
+	for _, rt := range r.routes {
+		if rt.name() != "" && rt.name() == name {
+			info := rt.info()
+			return &info
 		}
 	}
 
 	return nil
 }
+
+// withVariants inserts after each route the variants served through it
+// (see Consumes), so a listing from the matcher covers every registered
+// route.
+func withVariants(routes []*route) []*route {
+	expanded := make([]*route, 0, len(routes))
+	for _, rt := range routes {
+		if rt.variants != nil {
+			expanded = append(expanded, rt.variants...)
+			continue
+		}
+		expanded = append(expanded, rt)
+	}
+	return expanded
+}
+
+// info builds the introspection view of a route
+func (r *route) info() RouteInfo {
+	info := RouteInfo{
+		Method:  r.method,
+		Pattern: r.pattern,
+	}
+
+	if r.metadata != nil {
+		info.Name = r.metadata.Name
+		info.Description = r.metadata.Description
+		info.Tags = r.metadata.Tags
+		info.Deprecated = r.metadata.Deprecated
+		info.Sunset = r.metadata.Sunset
+		info.Version = r.metadata.Version
+	}
+	info.Consumes = r.consumes
+	info.Produces = r.produces
+
+	return info
+}
@@ -1,7 +1,12 @@
 package cosan
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRouteMetadata_WithName(t *testing.T) {
@@ -140,23 +145,50 @@ func TestRouter_GetRoutes(t *testing.T) {
 	}
 }
 
+func TestRouter_GetRoutesSorted(t *testing.T) {
+	router := New()
+	handler := func(ctx Context) error { return nil }
+	router.POST("/users", handler)
+	router.GET("/users/:id", handler, WithName("users.show"))
+	router.GET("/health", handler)
+	router.GET("/users", handler, WithName("users.list"), Deprecated())
+
+	var got []string
+	for _, info := range router.GetRoutesSorted() {
+		got = append(got, info.Method+" "+info.Pattern)
+	}
+	want := []string{"GET /health", "GET /users", "GET /users/:id", "POST /users"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if first := router.GetRoutes()[0]; first.Method != "POST" {
+		t.Errorf("Expected GetRoutes to keep registration order, got %s first", first.Method)
+	}
+
+	var buf bytes.Buffer
+	if err := router.DumpRoutes(&buf); err != nil {
+		t.Fatal(err)
+	}
+	wantDump := "METHOD  PATTERN     NAME\n" +
+		"GET     /health     \n" +
+		"GET     /users      users.list (deprecated)\n" +
+		"GET     /users/:id  users.show\n" +
+		"POST    /users      \n"
+	if buf.String() != wantDump {
+		t.Errorf("Unexpected dump:\n%s", buf.String())
+	}
+}
+
 func TestRouter_GetRoutesWithMetadata(t *testing.T) {
-	router := New().(*router)
-
-	// Create route with metadata
-	r := &route{
-		method:  "GET",
-		pattern: "/api/v1/users",
-		handler: func(ctx Context) error { return nil },
-		metadata: &RouteMetadata{
-			Name:        "list-users",
-			Description: "Lists all users",
-			Tags:        []string{"users", "api"},
-			Deprecated:  false,
-			Version:     "v1.0.0",
-		},
-	}
-	router.routes = append(router.routes, r)
+	router := New()
+
+	router.GET("/api/v1/users", func(ctx Context) error { return nil },
+		WithName("list-users"),
+		WithDescription("Lists all users"),
+		WithTags("users", "api"),
+		WithVersion("v1.0.0"),
+	)
 
 	routes := router.GetRoutes()
 	if len(routes) != 1 {
@@ -182,17 +214,9 @@ func TestRouter_GetRoutesWithMetadata(t *testing.T) {
 }
 
 func TestRouter_FindRoute(t *testing.T) {
-	router := New().(*router)
+	router := New()
 
-	r := &route{
-		method:  "GET",
-		pattern: "/users",
-		handler: func(ctx Context) error { return nil },
-		metadata: &RouteMetadata{
-			Name: "get-users",
-		},
-	}
-	router.routes = append(router.routes, r)
+	router.GET("/users", func(ctx Context) error { return nil }, WithName("get-users"))
 
 	found := router.FindRoute("get-users")
 	if found == nil {
@@ -210,7 +234,7 @@ func TestRouter_FindRoute(t *testing.T) {
 }
 
 func TestRouter_FindRoute_NotFound(t *testing.T) {
-	router := New().(*router)
+	router := New()
 
 	found := router.FindRoute("nonexistent")
 	if found != nil {
@@ -219,17 +243,213 @@ func TestRouter_FindRoute_NotFound(t *testing.T) {
 }
 
 func TestRouter_FindRoute_NoMetadata(t *testing.T) {
-	router := New().(*router)
+	router := New()
 
-	r := &route{
-		method:  "GET",
-		pattern: "/users",
-		handler: func(ctx Context) error { return nil },
-	}
-	router.routes = append(router.routes, r)
+	router.GET("/users", func(ctx Context) error { return nil })
 
 	found := router.FindRoute("get-users")
 	if found != nil {
 		t.Error("Should not find route without metadata name")
 	}
 }
+
+func TestRouter_DuplicateRouteName(t *testing.T) {
+	router := New()
+	handler := func(ctx Context) error { return nil }
+
+	router.GET("/users", handler, WithName("users.list"))
+
+	err := router.RegisterRoutes([]RouteDef{
+		{Method: http.MethodGet, Pattern: "/people", Handler: handler, Name: "users.list"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `duplicate route name "users.list": GET /people`) {
+		t.Errorf("Expected a duplicate route name error, got %v", err)
+	}
+
+	func() {
+		defer func() {
+			if rec := recover(); rec == nil || !strings.Contains(rec.(string), "duplicate route name") {
+				t.Errorf("Expected a duplicate route name panic, got %v", rec)
+			}
+		}()
+		router.POST("/users", handler, WithName("users.list"))
+	}()
+
+	if found := router.FindRoute("users.list"); found == nil || found.Pattern != "/users" || found.Method != http.MethodGet {
+		t.Errorf("Expected users.list to remain GET /users, got %+v", found)
+	}
+}
+
+func TestRouteMetadata_WithSunset(t *testing.T) {
+	r := &route{}
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	WithSunset(sunset)(r)
+
+	if !r.metadata.Sunset.Equal(sunset) {
+		t.Errorf("Expected sunset %v, got %v", sunset, r.metadata.Sunset)
+	}
+}
+
+func TestRouter_RouteOptionsOnRegistration(t *testing.T) {
+	router := New()
+	router.Group("/api").GET("/users", func(ctx Context) error { return nil },
+		WithName("list-users"), WithTags("users"))
+
+	found := router.FindRoute("list-users")
+	if found == nil {
+		t.Fatal("Route registered with WithName not found")
+	}
+	if found.Pattern != "/api/users" || len(found.Tags) != 1 {
+		t.Errorf("Unexpected route info: %+v", found)
+	}
+}
+
+func TestRouter_DeprecationHeaders(t *testing.T) {
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	router := New(WithDeprecationHeaders(true))
+	handler := func(ctx Context) error { return ctx.String(200, "ok") }
+	router.GET("/old", handler, Deprecated(), WithSunset(sunset))
+	router.GET("/legacy", handler, Deprecated())
+	router.GET("/new", handler)
+
+	tests := []struct {
+		path            string
+		wantDeprecation string
+		wantSunset      string
+	}{
+		{"/old", "true", "Fri, 01 Jan 2027 00:00:00 GMT"},
+		{"/legacy", "true", ""},
+		{"/new", "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if got := w.Header().Get("Deprecation"); got != tt.wantDeprecation {
+			t.Errorf("%s: expected Deprecation %q, got %q", tt.path, tt.wantDeprecation, got)
+		}
+		if got := w.Header().Get("Sunset"); got != tt.wantSunset {
+			t.Errorf("%s: expected Sunset %q, got %q", tt.path, tt.wantSunset, got)
+		}
+	}
+}
+
+func TestRouter_DeprecationHeadersDisabled(t *testing.T) {
+	router := New()
+	router.GET("/old", func(ctx Context) error { return ctx.String(200, "ok") }, Deprecated())
+
+	req := httptest.NewRequest(http.MethodGet, "/old", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Header().Get("Deprecation") != "" {
+		t.Error("Deprecation header should not be set unless enabled")
+	}
+}
+
+func TestRouter_GetRoutesRegistrationOrder(t *testing.T) {
+	router := New()
+	handler := func(ctx Context) error { return nil }
+	router.POST("/users", handler)
+	router.GET("/users/:id", handler)
+	router.GET("/files/*path", handler)
+	router.GET("/users", handler)
+
+	routes := router.GetRoutes()
+	want := []string{"POST /users", "GET /users/:id", "GET /files/*path", "GET /users"}
+	if len(routes) != len(want) {
+		t.Fatalf("Expected %d routes, got %d", len(want), len(routes))
+	}
+	for i, info := range routes {
+		if got := info.Method + " " + info.Pattern; got != want[i] {
+			t.Errorf("Route %d: expected %s, got %s", i, want[i], got)
+		}
+	}
+}
+
+func TestRouter_Version(t *testing.T) {
+	router := New()
+	handler := func(ctx Context) error { return ctx.String(200, "%s", ctx.RoutePattern()) }
+
+	v1 := router.Version("v1")
+	v1.GET("/users", handler)
+	v1.Group("/admin").DELETE("/users/:id", handler)
+	v1.GET("/legacy", handler, WithVersion("v0"))
+
+	router.Group("/api").Version("/v2/").GET("/users", handler)
+	router.GET("/health", handler)
+
+	want := map[string]string{
+		"GET /v1/users":              "v1",
+		"DELETE /v1/admin/users/:id": "v1",
+		"GET /v1/legacy":             "v0",
+		"GET /api/v2/users":          "v2",
+		"GET /health":                "",
+	}
+
+	routes := router.GetRoutes()
+	if len(routes) != len(want) {
+		t.Fatalf("Expected %d routes, got %d", len(want), len(routes))
+	}
+	for _, info := range routes {
+		key := info.Method + " " + info.Pattern
+		version, ok := want[key]
+		if !ok {
+			t.Errorf("Unexpected route %s", key)
+			continue
+		}
+		if info.Version != version {
+			t.Errorf("%s: expected version %q, got %q", key, version, info.Version)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v1/users", nil))
+	if w.Code != 200 || w.Body.String() != "/v1/users" {
+		t.Errorf("Expected 200 /v1/users, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestContext_RouteTags(t *testing.T) {
+	router := New()
+	router.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			for _, tag := range ctx.RouteTags() {
+				if tag == "admin" && ctx.Request().Header.Get("X-Role") != "admin" {
+					return NewHTTPError(http.StatusForbidden, "admin only")
+				}
+			}
+			return next(ctx)
+		}
+	}))
+
+	handler := func(ctx Context) error {
+		return ctx.String(200, "%s", strings.Join(ctx.RouteTags(), ","))
+	}
+	router.GET("/admin/stats", handler, WithTags("admin", "stats"))
+	router.GET("/public", handler)
+
+	tests := []struct {
+		path string
+		role string
+		code int
+		body string
+	}{
+		{"/admin/stats", "admin", 200, "admin,stats"},
+		{"/admin/stats", "user", 403, ""},
+		{"/public", "", 200, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("X-Role", tt.role)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.code || (tt.code == 200 && w.Body.String() != tt.body) {
+			t.Errorf("%s as %q: expected %d %q, got %d %q", tt.path, tt.role, tt.code, tt.body, w.Code, w.Body.String())
+		}
+	}
+}
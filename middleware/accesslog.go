@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+)
+
+// AccessLogFormat selects the line format written by AccessLog.
+type AccessLogFormat int
+
+const (
+	// CombinedLogFormat is the Apache/NGINX combined format: the common
+	// format followed by the quoted Referer and User-Agent.
+	CombinedLogFormat AccessLogFormat = iota
+
+	// CommonLogFormat is the NCSA common format:
+	// host ident user [time] "request" status bytes.
+	CommonLogFormat
+)
+
+// clfTimeFormat is the timestamp layout of the common log format.
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogConfig holds access log configuration.
+type AccessLogConfig struct {
+	// Output receives one line per request. Defaults to os.Stdout.
+	// Writes are serialized, so any io.Writer is safe to use.
+	Output io.Writer
+
+	// Format selects the line format. Defaults to CombinedLogFormat.
+	Format AccessLogFormat
+}
+
+// AccessLog returns a middleware that writes an access log line per request
+// in Combined (default) or Common Log Format, for log analyzers such as
+// GoAccess or AWStats.
+//
+// When a handler returns an error that is not yet written, the status is
+// taken from the error (the *cosan.HTTPError code, otherwise 500) and the
+// size is logged as "-", since the error handler writes the body later.
+//
+// Example:
+//
+// router.Use(middleware.AccessLog(middleware.AccessLogConfig{Output: logFile}))
+func AccessLog(config AccessLogConfig) cosan.Middleware {
+	out := config.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	var mu sync.Mutex
+
+	return cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+		return func(ctx cosan.Context) error {
+			start := time.Now()
+			err := next(ctx)
+
+			line := accessLogLine(ctx, err, start, config.Format)
+
+			mu.Lock()
+			_, _ = io.WriteString(out, line)
+			mu.Unlock()
+
+			return err
+		}
+	})
+}
+
+// accessLogLine formats the log line for a completed request.
+func accessLogLine(ctx cosan.Context, err error, start time.Time, format AccessLogFormat) string {
+	req := ctx.Request()
+
+	host := req.RemoteAddr
+	if h, _, splitErr := net.SplitHostPort(host); splitErr == nil {
+		host = h
+	}
+
+	uri := req.RequestURI
+	if uri == "" {
+		uri = req.URL.RequestURI()
+	}
+
+	user := "-"
+	if name, _, ok := req.BasicAuth(); ok && name != "" {
+		user = escapeLogUser(name)
+	}
+
+	status := responseStatus(ctx, err)
+	size := "-"
+	if n := ctx.BytesWritten(); n > 0 {
+		size = strconv.FormatInt(n, 10)
+	}
+
+	line := fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s",
+		host,
+		user,
+		start.Format(clfTimeFormat),
+		req.Method,
+		escapeLogField(uri),
+		req.Proto,
+		status,
+		size,
+	)
+
+	if format == CombinedLogFormat {
+		line += fmt.Sprintf(" \"%s\" \"%s\"",
+			escapeLogField(orDash(req.Referer())),
+			escapeLogField(orDash(req.UserAgent())),
+		)
+	}
+
+	return line + "\n"
+}
+
+// logFieldEscaper escapes backslashes, quotes, and control bytes, as
+// \\, \", \n, \r, \t, or \xhh.
+var logFieldEscaper = newLogFieldEscaper()
+
+// newLogFieldEscaper builds logFieldEscaper.
+func newLogFieldEscaper() *strings.Replacer {
+	pairs := []string{`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`}
+	for c := 0; c < 0x20; c++ {
+		if c != '\n' && c != '\r' && c != '\t' {
+			pairs = append(pairs, string(rune(c)), fmt.Sprintf(`\x%02x`, c))
+		}
+	}
+	pairs = append(pairs, "\x7f", `\x7f`)
+	return strings.NewReplacer(pairs...)
+}
+
+// escapeLogField escapes a client-controlled value so it cannot break out
+// of its quoted field or forge a line.
+func escapeLogField(s string) string {
+	return logFieldEscaper.Replace(s)
+}
+
+// escapeLogUser escapes the client-supplied user name, which is not
+// quoted, so a space cannot split it into two fields either.
+func escapeLogUser(s string) string {
+	return strings.ReplaceAll(escapeLogField(s), " ", `\x20`)
+}
+
+// orDash returns "-" for empty values, as the log formats require.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
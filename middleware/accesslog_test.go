@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/middleware"
+)
+
+func newAccessLogRouter(buf *bytes.Buffer, format middleware.AccessLogFormat) cosan.Router {
+	router := cosan.New()
+	router.Use(middleware.AccessLog(middleware.AccessLogConfig{Output: buf, Format: format}))
+	router.GET("/items", func(ctx cosan.Context) error {
+		return ctx.String(201, "hello")
+	})
+	router.GET("/missing", func(ctx cosan.Context) error {
+		return cosan.NewHTTPError(404, "no such item")
+	})
+	return router
+}
+
+func TestAccessLog_Combined(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(&buf, middleware.CombinedLogFormat)
+
+	req := httptest.NewRequest(http.MethodGet, "/items?page=2", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.SetBasicAuth("alice", "secret")
+	req.Header.Set("Referer", "https://example.com/")
+	req.Header.Set("User-Agent", `curl/8.0 "quoted"`)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := regexp.MustCompile(`^203\.0\.113\.7 - alice \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] ` +
+		`"GET /items\?page=2 HTTP/1\.1" 201 5 "https://example\.com/" "curl/8\.0 \\"quoted\\""\n$`)
+	if !want.MatchString(buf.String()) {
+		t.Errorf("Unexpected combined log line %q", buf.String())
+	}
+}
+
+func TestAccessLog_Common(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(&buf, middleware.CommonLogFormat)
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.RemoteAddr = "198.51.100.1:4000"
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	want := regexp.MustCompile(`^198\.51\.100\.1 - - \[[^\]]+\] "GET /missing HTTP/1\.1" 404 -\n$`)
+	if !want.MatchString(buf.String()) {
+		t.Errorf("Unexpected common log line %q", buf.String())
+	}
+}
+
+func TestAccessLog_EscapesClientFields(t *testing.T) {
+	var buf bytes.Buffer
+	router := newAccessLogRouter(&buf, middleware.CombinedLogFormat)
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.SetBasicAuth("eve x\n10.0.0.1 - admin [01/Jan/2026:00:00:00 +0000] \"GET /admin HTTP/1.1\" 200 -", "secret")
+	req.Header.Set("User-Agent", "agent\r\x00\x1b")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	if bytes.Count(buf.Bytes(), []byte("\n")) != 1 {
+		t.Fatalf("Expected a single log line, got %q", line)
+	}
+	want := regexp.MustCompile(`^203\.0\.113\.7 - eve\\x20x\\n10\.0\.0\.1\\x20-\\x20admin\\x20\S+ \[[^\]]+\] ` +
+		`"GET /items HTTP/1\.1" 201 5 "-" "agent\\r\\x00\\x1b"\n$`)
+	if !want.MatchString(line) {
+		t.Errorf("Unexpected log line %q", line)
+	}
+}
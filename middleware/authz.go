@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+)
+
+// RolesKey is the default context key RequireTags reads the user's roles
+// from. Authentication middleware stores them with ctx.Set as a []string.
+const RolesKey = "roles"
+
+// Authorizer decides whether the request may reach a route carrying
+// routeTags, the route's tags that RequireTags enforces.
+type Authorizer func(ctx cosan.Context, routeTags []string) bool
+
+// RequireTagsConfig holds tag-based authorization configuration.
+type RequireTagsConfig struct {
+	// Tags are the route tags (from cosan.WithTags) that require
+	// authorization; other tags are ignored. Empty enforces every tag.
+	Tags []string
+
+	// Authorizer decides whether the request may proceed. Defaults to
+	// requiring a role, read from RolesKey, for each enforced route tag.
+	Authorizer Authorizer
+
+	// RolesKey is the context key the default Authorizer reads the user's
+	// roles from. Defaults to RolesKey.
+	RolesKey string
+}
+
+// RequireTags returns a middleware that authorizes requests by the matched
+// route's tags: a route carrying any of tags is only served to users whose
+// roles, stored under RolesKey, include each of them. Other requests fail
+// with an *cosan.HTTPError 403. Routes carrying none of tags are not
+// checked.
+//
+// Example:
+//
+// router.Use(auth, middleware.RequireTags("admin"))
+// router.GET("/admin/stats", Stats, cosan.WithTags("admin"))
+func RequireTags(tags ...string) cosan.Middleware {
+	return RequireTagsWithConfig(RequireTagsConfig{Tags: tags})
+}
+
+// RequireTagsWithConfig returns a tag-based authorization middleware with
+// custom configuration.
+//
+// Example:
+//
+// router.Use(middleware.RequireTagsWithConfig(middleware.RequireTagsConfig{
+// Tags: []string{"admin", "billing"},
+// Authorizer: func(ctx cosan.Context, routeTags []string) bool {
+// return currentUser(ctx).CanAccess(routeTags)
+// },
+// }))
+func RequireTagsWithConfig(config RequireTagsConfig) cosan.Middleware {
+	if config.RolesKey == "" {
+		config.RolesKey = RolesKey
+	}
+	if config.Authorizer == nil {
+		config.Authorizer = rolesAuthorizer(config.RolesKey)
+	}
+
+	return cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+		return func(ctx cosan.Context) error {
+			var enforced []string
+			for _, tag := range ctx.RouteTags() {
+				if len(config.Tags) == 0 || contains(config.Tags, tag) {
+					enforced = append(enforced, tag)
+				}
+			}
+
+			if len(enforced) > 0 && !config.Authorizer(ctx, enforced) {
+				return cosan.NewHTTPError(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+			}
+			return next(ctx)
+		}
+	})
+}
+
+// rolesAuthorizer allows requests whose roles under key include every
+// route tag.
+func rolesAuthorizer(key string) Authorizer {
+	return func(ctx cosan.Context, routeTags []string) bool {
+		roles, _ := ctx.Get(key).([]string)
+		for _, tag := range routeTags {
+			if !contains(roles, tag) {
+				return false
+			}
+		}
+		return true
+	}
+}
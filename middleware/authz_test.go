@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/middleware"
+)
+
+// withRoles stores the comma-separated X-Roles header as the user's roles.
+var withRoles = cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+	return func(ctx cosan.Context) error {
+		if roles := ctx.Request().Header.Get("X-Roles"); roles != "" {
+			ctx.Set(middleware.RolesKey, strings.Split(roles, ","))
+		}
+		return next(ctx)
+	}
+})
+
+func TestRequireTags(t *testing.T) {
+	router := cosan.New()
+	router.Use(withRoles, middleware.RequireTags("admin", "billing"))
+
+	ok := func(ctx cosan.Context) error { return ctx.String(200, "OK") }
+	router.GET("/admin", ok, cosan.WithTags("admin", "stats"))
+	router.GET("/invoices", ok, cosan.WithTags("admin", "billing"))
+	router.GET("/public", ok, cosan.WithTags("stats"))
+
+	tests := []struct {
+		path  string
+		roles string
+		code  int
+	}{
+		{"/admin", "admin", 200},
+		{"/admin", "user", 403},
+		{"/admin", "", 403},
+		{"/invoices", "admin,billing", 200},
+		{"/invoices", "admin", 403},
+		{"/public", "", 200},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("X-Roles", tt.roles)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s with roles %q: expected %d, got %d", tt.path, tt.roles, tt.code, w.Code)
+		}
+	}
+}
+
+func TestRequireTagsWithConfig_Authorizer(t *testing.T) {
+	var seen []string
+	router := cosan.New()
+	router.Use(middleware.RequireTagsWithConfig(middleware.RequireTagsConfig{
+		Tags: []string{"admin"},
+		Authorizer: func(ctx cosan.Context, routeTags []string) bool {
+			seen = routeTags
+			return ctx.Request().Header.Get("Authorization") == "Bearer admin"
+		},
+	}))
+	router.GET("/admin", func(ctx cosan.Context) error {
+		return ctx.String(200, "OK")
+	}, cosan.WithTags("reports", "admin"))
+
+	for _, tt := range []struct {
+		auth string
+		code int
+	}{
+		{"Bearer admin", 200},
+		{"Bearer user", 403},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", tt.auth)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.auth, tt.code, w.Code)
+		}
+	}
+
+	if len(seen) != 1 || seen[0] != "admin" {
+		t.Errorf("Expected the authorizer to receive only the enforced tags, got %v", seen)
+	}
+}
@@ -0,0 +1,45 @@
+// Package brotli provides a Brotli encoder for the cosan compression middleware.
+//
+// It lives in its own module so the core router stays dependency-free;
+// only applications that import this package pull in the Brotli library.
+//
+// Example:
+//
+//	router.Use(middleware.CompressWithConfig(middleware.CompressConfig{
+//	    Encoders: []middleware.Encoder{
+//	        brotli.NewEncoder(brotli.DefaultCompression),
+//	        middleware.NewGzipEncoder(gzip.DefaultCompression),
+//	    },
+//	}))
+package brotli
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/toutaio/toutago-cosan-router/middleware"
+)
+
+// DefaultCompression is a Brotli quality suited to on-the-fly compression.
+const DefaultCompression = 5
+
+// encoder implements middleware.Encoder using Brotli.
+type encoder struct {
+	level int
+}
+
+// NewEncoder returns a Brotli middleware.Encoder with the given quality (0-11).
+func NewEncoder(level int) middleware.Encoder {
+	return &encoder{level: level}
+}
+
+// Encoding returns "br".
+func (e *encoder) Encoding() string {
+	return "br"
+}
+
+// NewWriter returns a Brotli writer.
+func (e *encoder) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return brotli.NewWriterLevel(w, e.level), nil
+}
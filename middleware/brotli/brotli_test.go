@@ -0,0 +1,71 @@
+package brotli_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/middleware"
+	cosanbrotli "github.com/toutaio/toutago-cosan-router/middleware/brotli"
+)
+
+func newRouter() cosan.Router {
+	router := cosan.New()
+	router.Use(middleware.CompressWithConfig(middleware.CompressConfig{
+		Encoders: []middleware.Encoder{
+			cosanbrotli.NewEncoder(cosanbrotli.DefaultCompression),
+			middleware.NewGzipEncoder(gzip.DefaultCompression),
+		},
+	}))
+	router.GET("/", func(ctx cosan.Context) error {
+		return ctx.String(200, "hello brotli")
+	})
+	return router
+}
+
+func TestBrotliSelected(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "br" {
+		t.Fatalf("Expected br encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	body, err := io.ReadAll(brotli.NewReader(w.Body))
+	if err != nil {
+		t.Fatalf("Failed to decode brotli body: %v", err)
+	}
+	if string(body) != "hello brotli" {
+		t.Errorf("Expected 'hello brotli', got %q", body)
+	}
+}
+
+func TestBrotliFallsBackToGzip(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("Expected gzip encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestBrotliIdentity(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected no encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if w.Body.String() != "hello brotli" {
+		t.Errorf("Expected plain body, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/toutaio/toutago-cosan-router/middleware/brotli
+
+go 1.22
+
+require (
+	github.com/andybalholm/brotli v1.2.0
+	github.com/toutaio/toutago-cosan-router v1.1.0
+)
+
+replace github.com/toutaio/toutago-cosan-router => ../..
@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+)
+
+// Encoder produces compressed response bodies for a single content coding.
+// Implementations for codings outside the standard library (such as Brotli)
+// live in their own subpackages so the core module stays dependency-free.
+type Encoder interface {
+	// Encoding returns the content-coding token, e.g. "gzip" or "br".
+	Encoding() string
+
+	// NewWriter returns a writer that compresses everything written to it into w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+// CompressConfig holds compression configuration.
+type CompressConfig struct {
+	// Encoders lists the available encoders in priority order.
+	// When the client weights several encodings equally, the one listed
+	// first wins. Defaults to gzip only.
+	Encoders []Encoder
+}
+
+// Compress returns a middleware that gzip-compresses responses for clients
+// that accept it.
+//
+// Example:
+//
+// router.Use(middleware.Compress())
+func Compress() cosan.Middleware {
+	return CompressWithConfig(CompressConfig{})
+}
+
+// CompressWithConfig returns a compression middleware with custom configuration.
+// The encoding is negotiated from the request's Accept-Encoding header,
+// honoring q-values; ties are broken by the order of config.Encoders.
+//
+// Example:
+//
+// router.Use(middleware.CompressWithConfig(middleware.CompressConfig{
+// Encoders: []middleware.Encoder{brotli.NewEncoder(5), middleware.NewGzipEncoder(gzip.DefaultCompression)},
+// }))
+func CompressWithConfig(config CompressConfig) cosan.Middleware {
+	encoders := config.Encoders
+	if len(encoders) == 0 {
+		encoders = []Encoder{NewGzipEncoder(gzip.DefaultCompression)}
+	}
+
+	return cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+		return func(ctx cosan.Context) error {
+			ctx.Header().Add("Vary", "Accept-Encoding")
+
+			encoder := negotiateEncoding(ctx.Request().Header.Get("Accept-Encoding"), encoders)
+			if encoder == nil {
+				return next(ctx)
+			}
+
+			original := ctx.Response()
+			cw := &compressWriter{ResponseWriter: original, encoder: encoder}
+			ctx.SetResponse(cw)
+
+			err := next(ctx)
+			if closeErr := cw.Close(); err == nil {
+				err = closeErr
+			}
+			ctx.SetResponse(original)
+
+			return err
+		}
+	})
+}
+
+// gzipEncoder implements Encoder using compress/gzip.
+type gzipEncoder struct {
+	level int
+}
+
+// NewGzipEncoder returns a gzip Encoder with the given compression level.
+func NewGzipEncoder(level int) Encoder {
+	return &gzipEncoder{level: level}
+}
+
+// Encoding returns "gzip".
+func (e *gzipEncoder) Encoding() string {
+	return "gzip"
+}
+
+// NewWriter returns a gzip writer.
+func (e *gzipEncoder) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, e.level)
+}
+
+// compressWriter wraps http.ResponseWriter to compress the response body.
+// Compression is decided when the header is written, so responses without
+// a body (204, 304) or already encoded by the handler pass through untouched.
+// A compressed response's header is held until the first body bytes, so a
+// missing Content-Type is detected from the uncompressed body rather than
+// sniffed by net/http from the compressed one.
+type compressWriter struct {
+	http.ResponseWriter
+	encoder     Encoder
+	writer      io.WriteCloser
+	code        int
+	wroteHeader bool
+	headerSent  bool // header written to the underlying writer
+	passthrough bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.code = code
+
+	header := w.Header()
+	if code < 200 || code == http.StatusNoContent || code == http.StatusNotModified ||
+		header.Get("Content-Encoding") != "" {
+		w.passthrough = true
+		w.sendHeader()
+		return
+	}
+	header.Set("Content-Encoding", w.encoder.Encoding())
+	header.Del("Content-Length")
+}
+
+// sendHeader writes the held header to the underlying writer once.
+func (w *compressWriter) sendHeader() {
+	if w.headerSent {
+		return
+	}
+	w.headerSent = true
+	w.ResponseWriter.WriteHeader(w.code)
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	if !w.headerSent {
+		if w.Header().Get("Content-Type") == "" && len(b) > 0 {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.sendHeader()
+	}
+	if w.writer == nil {
+		zw, err := w.encoder.NewWriter(w.ResponseWriter)
+		if err != nil {
+			return 0, err
+		}
+		w.writer = zw
+	}
+	return w.writer.Write(b)
+}
+
+// Flush implements http.Flusher, flushing the encoder's buffered output
+// before flushing the underlying writer.
+func (w *compressWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.sendHeader()
+	if f, ok := w.writer.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker by delegating to the underlying writer.
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close sends a header still held back and flushes any buffered
+// compressed data.
+func (w *compressWriter) Close() error {
+	if w.wroteHeader {
+		w.sendHeader()
+	}
+	if w.writer == nil {
+		return nil
+	}
+	return w.writer.Close()
+}
+
+// negotiateEncoding picks the encoder preferred by the Accept-Encoding header.
+// It returns nil when identity (no compression) should be used.
+func negotiateEncoding(acceptEncoding string, encoders []Encoder) Encoder {
+	if acceptEncoding == "" {
+		return nil
+	}
+
+	weights := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, q := parseQuality(part)
+		if coding == "" {
+			continue
+		}
+		if coding == "*" {
+			wildcard = q
+			continue
+		}
+		weights[coding] = q
+	}
+
+	var best Encoder
+	bestQ := 0.0
+	for _, enc := range encoders {
+		q, ok := weights[enc.Encoding()]
+		if !ok {
+			if wildcard < 0 {
+				continue
+			}
+			q = wildcard
+		}
+		// Strictly greater keeps the configured priority on ties.
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+
+	// An explicitly preferred identity coding beats any compressed one.
+	if identityQ, ok := weights["identity"]; ok && identityQ > bestQ {
+		return nil
+	}
+
+	return best
+}
+
+// parseQuality splits an Accept-Encoding element into its coding and q-value.
+func parseQuality(part string) (string, float64) {
+	coding, params, _ := strings.Cut(part, ";")
+	coding = strings.ToLower(strings.TrimSpace(coding))
+
+	q := 1.0
+	for _, param := range strings.Split(params, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || strings.TrimSpace(key) != "q" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return coding, 0
+		}
+		q = parsed
+	}
+
+	return coding, q
+}
@@ -0,0 +1,203 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/middleware"
+)
+
+// stubEncoder is a fake "br" encoder that upper-cases the body.
+type stubEncoder struct{}
+
+func (stubEncoder) Encoding() string { return "br" }
+
+func (stubEncoder) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return upperWriter{w}, nil
+}
+
+type upperWriter struct{ io.Writer }
+
+func (u upperWriter) Write(b []byte) (int, error) {
+	return u.Writer.Write([]byte(strings.ToUpper(string(b))))
+}
+
+func (upperWriter) Close() error { return nil }
+
+func newCompressRouter() cosan.Router {
+	router := cosan.New()
+	router.Use(middleware.CompressWithConfig(middleware.CompressConfig{
+		Encoders: []middleware.Encoder{stubEncoder{}, middleware.NewGzipEncoder(gzip.BestSpeed)},
+	}))
+	router.GET("/text", func(ctx cosan.Context) error {
+		return ctx.String(200, "hello world")
+	})
+	return router
+}
+
+func TestCompressNegotiation(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{"br preferred", "gzip, br", "br"},
+		{"br by priority on tie", "br;q=0.8, gzip;q=0.8", "br"},
+		{"gzip by q-value", "br;q=0.5, gzip", "gzip"},
+		{"gzip fallback", "gzip, deflate", "gzip"},
+		{"identity when nothing accepted", "deflate", ""},
+		{"identity when header missing", "", ""},
+		{"identity preferred", "identity, gzip;q=0.5", ""},
+		{"br rejected", "br;q=0, gzip;q=0.1", "gzip"},
+		{"wildcard", "*", "br"},
+	}
+
+	router := newCompressRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/text", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Expected Content-Encoding %q, got %q", tt.wantEncoding, got)
+			}
+			if w.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("Expected Vary: Accept-Encoding, got %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCompressGzipBody(t *testing.T) {
+	router := newCompressRouter()
+	req := httptest.NewRequest(http.MethodGet, "/text", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Invalid gzip body: %v", err)
+	}
+	body, _ := io.ReadAll(zr)
+	if string(body) != "hello world" {
+		t.Errorf("Expected 'hello world', got %q", body)
+	}
+}
+
+func TestCompressDetectsContentType(t *testing.T) {
+	router := cosan.New()
+	router.Use(middleware.Compress())
+	router.GET("/page", func(ctx cosan.Context) error {
+		_, err := ctx.Response().Write([]byte("<!DOCTYPE html><html><body>hi</body></html>"))
+		return err
+	})
+	router.GET("/created", func(ctx cosan.Context) error {
+		ctx.Response().WriteHeader(http.StatusCreated)
+		_, err := ctx.Response().Write([]byte("plain text"))
+		return err
+	})
+
+	tests := []struct {
+		path string
+		code int
+		want string
+	}{
+		{"/page", 200, "text/html; charset=utf-8"},
+		{"/created", 201, "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.code || w.Header().Get("Content-Encoding") != "gzip" {
+			t.Errorf("%s: expected gzipped %d, got %d %q", tt.path, tt.code, w.Code, w.Header().Get("Content-Encoding"))
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.want {
+			t.Errorf("%s: expected Content-Type %q, got %q", tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestCompressBrBody(t *testing.T) {
+	router := newCompressRouter()
+	req := httptest.NewRequest(http.MethodGet, "/text", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != "HELLO WORLD" {
+		t.Errorf("Expected body encoded by br encoder, got %q", w.Body.String())
+	}
+}
+
+func TestCompressSkipsNoContent(t *testing.T) {
+	router := cosan.New()
+	router.Use(middleware.Compress())
+	router.DELETE("/item", func(ctx cosan.Context) error {
+		ctx.Status(204)
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/item", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Error("Expected no Content-Encoding on 204 response")
+	}
+}
+
+func TestCompressFlushPassthrough(t *testing.T) {
+	router := cosan.New()
+	router.Use(middleware.Compress())
+
+	var flushErr, hijackErr error
+	router.GET("/events", func(ctx cosan.Context) error {
+		ctx.Write([]byte("data: ping\n\n"))
+		flushErr = ctx.Flush()
+		_, _, hijackErr = ctx.Hijack()
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if flushErr != nil {
+		t.Fatalf("Flush through compress writer failed: %v", flushErr)
+	}
+	if !w.Flushed {
+		t.Error("Expected underlying writer to be flushed")
+	}
+	if !errors.Is(hijackErr, http.ErrNotSupported) {
+		t.Errorf("Expected http.ErrNotSupported from recorder hijack, got %v", hijackErr)
+	}
+
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Invalid gzip body: %v", err)
+	}
+	body, _ := io.ReadAll(zr)
+	if string(body) != "data: ping\n\n" {
+		t.Errorf("Unexpected body %q", body)
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+)
+
+// DefaultMaxDecompressedSize is the default limit on a decompressed
+// request body.
+const DefaultMaxDecompressedSize = 10 << 20
+
+// DecompressConfig holds request decompression configuration.
+type DecompressConfig struct {
+	// MaxSize limits the decompressed body in bytes, so a small compressed
+	// body cannot expand into an unbounded one. Reading past it fails with
+	// an *cosan.HTTPError 413. Defaults to DefaultMaxDecompressedSize; a
+	// negative value disables the limit.
+	MaxSize int64
+}
+
+// DecompressRequest returns a middleware that transparently decompresses
+// gzip and deflate request bodies, so Bind and BodyBytes see the original
+// bytes.
+//
+// Example:
+//
+// router.Use(middleware.DecompressRequest())
+func DecompressRequest() cosan.Middleware {
+	return DecompressRequestWithConfig(DecompressConfig{})
+}
+
+// DecompressRequestWithConfig returns a request decompression middleware
+// with custom configuration. Bodies with Content-Encoding gzip (or x-gzip)
+// or deflate are replaced by a decompressing reader, and the
+// Content-Encoding and Content-Length headers are removed. Other encodings
+// fail with an *cosan.HTTPError 415, and a body that is not valid for its
+// encoding with a 400.
+func DecompressRequestWithConfig(config DecompressConfig) cosan.Middleware {
+	maxSize := config.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxDecompressedSize
+	}
+
+	return cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+		return func(ctx cosan.Context) error {
+			req := ctx.Request()
+			encoding := strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding")))
+			if encoding == "" || encoding == "identity" || req.Body == nil || req.Body == http.NoBody {
+				return next(ctx)
+			}
+
+			var body io.ReadCloser
+			var err error
+			switch encoding {
+			case "gzip", "x-gzip":
+				body, err = gzip.NewReader(req.Body)
+			case "deflate":
+				body, err = zlib.NewReader(req.Body)
+			default:
+				return cosan.NewHTTPError(http.StatusUnsupportedMediaType, "unsupported Content-Encoding "+encoding)
+			}
+			if err != nil {
+				return &cosan.HTTPError{Code: http.StatusBadRequest, Message: "invalid " + encoding + " request body", Err: err}
+			}
+
+			req.Body = &decompressedBody{reader: body, original: req.Body, remaining: maxSize}
+			req.ContentLength = -1
+			req.Header.Del("Content-Encoding")
+			req.Header.Del("Content-Length")
+
+			return next(ctx)
+		}
+	})
+}
+
+// decompressedBody reads a decompressing reader, failing once more than
+// remaining bytes have been produced, unless remaining is negative.
+type decompressedBody struct {
+	reader    io.ReadCloser
+	original  io.ReadCloser
+	remaining int64
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return b.reader.Read(p)
+	}
+
+	// Read one byte beyond the limit to tell an exact fit from an overflow
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.reader.Read(p)
+	if int64(n) > b.remaining {
+		n, b.remaining = int(b.remaining), 0
+		return n, cosan.NewHTTPError(http.StatusRequestEntityTooLarge, "decompressed request body too large")
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// Close closes the decompressor and the underlying body.
+func (b *decompressedBody) Close() error {
+	err := b.reader.Close()
+	if closeErr := b.original.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
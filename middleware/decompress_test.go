@@ -0,0 +1,121 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/middleware"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func deflateBytes(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newDecompressRouter(config middleware.DecompressConfig) cosan.Router {
+	router := cosan.New()
+	router.Use(middleware.DecompressRequestWithConfig(config))
+	router.POST("/users", func(ctx cosan.Context) error {
+		var user struct {
+			Name string `json:"name"`
+		}
+		if err := ctx.Bind(&user); err != nil {
+			return err
+		}
+		return ctx.String(200, "hello %s", user.Name)
+	})
+	return router
+}
+
+func TestDecompressRequest(t *testing.T) {
+	payload := `{"name":"alice"}`
+	large := `{"name":"` + strings.Repeat("a", 1000) + `"}`
+
+	tests := []struct {
+		name     string
+		config   middleware.DecompressConfig
+		encoding string
+		body     []byte
+		code     int
+		want     string
+	}{
+		{"gzip", middleware.DecompressConfig{}, "gzip", gzipBytes(t, payload), 200, "hello alice"},
+		{"deflate", middleware.DecompressConfig{}, "deflate", deflateBytes(t, payload), 200, "hello alice"},
+		{"identity", middleware.DecompressConfig{}, "", []byte(payload), 200, "hello alice"},
+		{"unsupported", middleware.DecompressConfig{}, "compress", []byte(payload), 415, ""},
+		{"corrupt", middleware.DecompressConfig{}, "gzip", []byte(payload), 400, ""},
+		{"over limit", middleware.DecompressConfig{MaxSize: 100}, "gzip", gzipBytes(t, large), 413, ""},
+		{"limit disabled", middleware.DecompressConfig{MaxSize: -1}, "gzip", gzipBytes(t, large), 200, "hello " + strings.Repeat("a", 1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+			newDecompressRouter(tt.config).ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Errorf("Expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if tt.want != "" && w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDecompressRequest_Headers(t *testing.T) {
+	router := cosan.New()
+	router.Use(middleware.DecompressRequest())
+	router.POST("/raw", func(ctx cosan.Context) error {
+		req := ctx.Request()
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		return ctx.String(200, "%s|%s|%d", body, req.Header.Get("Content-Encoding"), req.ContentLength)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/raw", bytes.NewReader(gzipBytes(t, "plain")))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != "plain||-1" {
+		t.Errorf("Expected decoded body without encoding headers, got %q", w.Body.String())
+	}
+}
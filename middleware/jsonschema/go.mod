@@ -0,0 +1,12 @@
+module github.com/toutaio/toutago-cosan-router/middleware/jsonschema
+
+go 1.22
+
+require (
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.2
+	github.com/toutaio/toutago-cosan-router v1.1.0
+)
+
+require golang.org/x/text v0.14.0 // indirect
+
+replace github.com/toutaio/toutago-cosan-router => ../..
@@ -0,0 +1,157 @@
+// Package jsonschema provides middleware that validates JSON request
+// bodies against a JSON Schema before the handler runs.
+//
+// It lives in its own module so the core router stays dependency-free;
+// only applications that import this package pull in the schema library.
+//
+// Example:
+//
+//	router.Route("/users").
+//		Use(jsonschema.Validate([]byte(`{
+//			"type": "object",
+//			"required": ["name"],
+//			"properties": {"name": {"type": "string", "minLength": 1}}
+//		}`))).
+//		POST(CreateUser)
+package jsonschema
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+)
+
+// schemaURL is the location the schema document is registered under.
+const schemaURL = "request-schema.json"
+
+// Config holds schema validation configuration.
+type Config struct {
+	// MaxBytes limits the request body read for validation, so an oversized
+	// body is rejected before it is held in memory. A larger body fails
+	// with a *cosan.HTTPError 413. Defaults to cosan.DefaultMaxBindBytes; a
+	// negative value disables the limit.
+	MaxBytes int64
+}
+
+// Validate returns middleware that validates each request body against
+// schema, a JSON Schema document (draft 2020-12 unless it declares another
+// $schema). A body that is not JSON or does not match the schema fails
+// with a *cosan.HTTPError 400 whose message lists every violation, so the
+// handler never sees it; a valid body stays readable through Bind. Bodies
+// over cosan.DefaultMaxBindBytes fail with a 413; see ValidateWithConfig.
+//
+// Validate panics if schema does not compile, since that is a programming
+// error, as with invalid route patterns.
+func Validate(schema []byte) cosan.Middleware {
+	return ValidateWithConfig(schema, Config{})
+}
+
+// ValidateWithConfig returns schema validation middleware with custom
+// configuration.
+//
+// Example:
+//
+//	router.Route("/imports").
+//		Use(jsonschema.ValidateWithConfig(importSchema, jsonschema.Config{MaxBytes: 50 << 20})).
+//		POST(ImportHandler, cosan.WithBindLimit(50<<20))
+func ValidateWithConfig(schema []byte, config Config) cosan.Middleware {
+	compiled, err := compile(schema)
+	if err != nil {
+		panic("cosan: invalid JSON schema: " + err.Error())
+	}
+
+	maxBytes := config.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = cosan.DefaultMaxBindBytes
+	}
+
+	return cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+		return func(ctx cosan.Context) error {
+			body, err := readBody(ctx, maxBytes)
+			if err != nil {
+				return err
+			}
+
+			instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+			if err != nil {
+				return &cosan.HTTPError{Code: http.StatusBadRequest, Message: "request body is not valid JSON", Err: err}
+			}
+			if err := compiled.Validate(instance); err != nil {
+				return &cosan.HTTPError{Code: http.StatusBadRequest, Message: violations(err), Err: err}
+			}
+
+			return next(ctx)
+		}
+	})
+}
+
+// readBody reads the request body through ctx.BodyBytes, so it stays
+// readable, failing with a 413 once it exceeds limit (no limit if
+// negative).
+func readBody(ctx cosan.Context, limit int64) ([]byte, error) {
+	if limit < 0 {
+		return ctx.BodyBytes()
+	}
+
+	req := ctx.Request()
+	if req.ContentLength > limit {
+		return nil, tooLarge(nil)
+	}
+	req.Body = http.MaxBytesReader(ctx.Response(), req.Body, limit)
+
+	body, err := ctx.BodyBytes()
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) || int64(len(body)) > limit {
+		return nil, tooLarge(err)
+	}
+	return body, err
+}
+
+// tooLarge returns the 413 error for a body over the limit.
+func tooLarge(err error) error {
+	return &cosan.HTTPError{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: http.StatusText(http.StatusRequestEntityTooLarge),
+		Err:     err,
+	}
+}
+
+// compile compiles a schema document.
+func compile(schema []byte) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaURL)
+}
+
+// violations describes a validation failure, one violation per clause:
+// "request body does not match schema: at '/age': got string, want integer".
+func violations(err error) string {
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return "request body does not match schema"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("request body does not match schema")
+	sep := ": "
+	for _, unit := range verr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		sb.WriteString(sep + "at '" + unit.InstanceLocation + "': " + unit.Error.String())
+		sep = "; "
+	}
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+package jsonschema_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cosan "github.com/toutaio/toutago-cosan-router"
+	"github.com/toutaio/toutago-cosan-router/middleware/jsonschema"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["name"],
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"age": {"type": "integer", "minimum": 0}
+	}
+}`
+
+func newRouter() cosan.Router {
+	router := cosan.New()
+	router.Route("/users").
+		Use(jsonschema.Validate([]byte(userSchema))).
+		POST(func(ctx cosan.Context) error {
+			var user struct {
+				Name string `json:"name"`
+				Age  int    `json:"age"`
+			}
+			if err := ctx.Bind(&user); err != nil {
+				return err
+			}
+			return ctx.String(201, "created %s (%d)", user.Name, user.Age)
+		})
+	return router
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+		want string
+	}{
+		{"valid", `{"name":"alice","age":30}`, 201, "created alice (30)"},
+		{"missing property", `{"age":30}`, 400, "at '': missing property 'name'"},
+		{"wrong types", `{"name":"","age":"thirty"}`, 400, "at '/age': got string, want integer"},
+		{"not JSON", `{"name":`, 400, "request body is not valid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Errorf("Expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("Expected body to contain %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidate_InvalidSchema(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Validate to panic for an invalid schema")
+		}
+	}()
+	jsonschema.Validate([]byte(`{"type": 42}`))
+}
+
+func TestValidateWithConfig_MaxBytes(t *testing.T) {
+	router := cosan.New()
+	router.Route("/users").
+		Use(jsonschema.ValidateWithConfig([]byte(userSchema), jsonschema.Config{MaxBytes: 32})).
+		POST(func(ctx cosan.Context) error {
+			return ctx.String(201, "created")
+		})
+
+	large := `{"name":"` + strings.Repeat("a", 64) + `"}`
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		code          int
+	}{
+		{"within limit", `{"name":"alice"}`, 16, 201},
+		{"declared too large", large, int64(len(large)), 413},
+		{"streamed too large", large, -1, 413},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.ContentLength = tt.contentLength
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Errorf("Expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -10,7 +10,9 @@ import (
 )
 
 // Logger returns a middleware that logs HTTP requests.
-// It logs the method, path, status code, and duration.
+// It logs the method, path, matched route pattern, status code, response
+// size, and duration. Unmatched requests log cosan.NotFoundLabel or
+// cosan.MethodNotAllowedLabel as their pattern.
 //
 // Example:
 //
@@ -28,10 +30,12 @@ func Logger() cosan.Middleware {
 			// Log after response
 			duration := time.Since(start)
 
-			log.Printf("[%s] %s %s (%v)",
+			log.Printf("[%s] %s %s %d %dB (%v)",
 				method,
 				path,
-				statusFromError(err),
+				ctx.RoutePattern(),
+				responseStatus(ctx, err),
+				ctx.BytesWritten(),
 				duration,
 			)
 
@@ -47,6 +51,32 @@ func Logger() cosan.Middleware {
 //
 // router.Use(middleware.Recovery())
 func Recovery() cosan.Middleware {
+	return RecoveryWithConfig(RecoveryConfig{})
+}
+
+// RecoveryConfig holds panic recovery configuration.
+type RecoveryConfig struct {
+	// ResponseFunc writes the response for a recovered panic.
+	// Defaults to a JSON 500 body. The panic and stack trace are logged
+	// regardless of the response format.
+	ResponseFunc func(ctx cosan.Context, recovered interface{})
+}
+
+// RecoveryWithConfig returns a recovery middleware with custom configuration.
+//
+// Example:
+//
+// router.Use(middleware.RecoveryWithConfig(middleware.RecoveryConfig{
+// ResponseFunc: func(ctx cosan.Context, recovered interface{}) {
+// _ = ctx.HTML(500, "<h1>Something went wrong</h1>")
+// },
+// }))
+func RecoveryWithConfig(config RecoveryConfig) cosan.Middleware {
+	respond := config.ResponseFunc
+	if respond == nil {
+		respond = jsonRecoveryResponse
+	}
+
 	return cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
 		return func(ctx cosan.Context) error {
 			defer func() {
@@ -54,10 +84,7 @@ func Recovery() cosan.Middleware {
 					// Log the panic and stack trace
 					log.Printf("PANIC: %v\n%s", r, debug.Stack())
 
-					// Return 500 error
-					ctx.Status(500)
-					ctx.Header().Set("Content-Type", "application/json")
-					_, _ = ctx.Write([]byte(fmt.Sprintf(`{"error":"Internal Server Error","message":"%v"}`, r)))
+					respond(ctx, r)
 				}
 			}()
 
@@ -66,28 +93,76 @@ func Recovery() cosan.Middleware {
 	})
 }
 
+// jsonRecoveryResponse writes the default JSON 500 body for a recovered panic.
+func jsonRecoveryResponse(ctx cosan.Context, recovered interface{}) {
+	_ = ctx.JSON(500, map[string]string{
+		"error":   "Internal Server Error",
+		"message": fmt.Sprint(recovered),
+	})
+}
+
 // RequestID returns a middleware that adds a unique request ID.
 // The ID is stored in the context and added to response headers.
 //
 // Example:
 //
 // router.Use(middleware.RequestID())
-// // In handler: id := ctx.Get("requestID").(string)
+// // In handler: id := ctx.Get(cosan.RequestIDKey).(string)
 func RequestID() cosan.Middleware {
+	return RequestIDWithConfig(RequestIDConfig{})
+}
+
+// RequestIDConfig holds request ID configuration.
+type RequestIDConfig struct {
+	// Header is the request and response header carrying the ID.
+	// Defaults to "X-Request-ID".
+	Header string
+
+	// ContextKey is the key the ID is stored under with ctx.Set.
+	// Defaults to cosan.RequestIDKey, which ctx.Logger also reads.
+	ContextKey string
+
+	// Generator creates an ID when the request has none.
+	// Defaults to the current time in nanoseconds.
+	Generator func() string
+}
+
+// RequestIDWithConfig returns a request ID middleware with custom
+// configuration. An ID already present in the configured header is reused,
+// so IDs propagate across services.
+//
+// Example:
+//
+// router.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+// Header:    "X-Correlation-ID",
+// Generator: uuid.NewString,
+// }))
+func RequestIDWithConfig(config RequestIDConfig) cosan.Middleware {
+	if config.Header == "" {
+		config.Header = "X-Request-ID"
+	}
+	if config.ContextKey == "" {
+		config.ContextKey = cosan.RequestIDKey
+	}
+	if config.Generator == nil {
+		config.Generator = func() string {
+			return fmt.Sprintf("%d", time.Now().UnixNano())
+		}
+	}
+
 	return cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
 		return func(ctx cosan.Context) error {
 			// Check if request ID already exists in header
-			requestID := ctx.Request().Header.Get("X-Request-ID")
+			requestID := ctx.Request().Header.Get(config.Header)
 			if requestID == "" {
-				// Generate a new request ID
-				requestID = fmt.Sprintf("%d", time.Now().UnixNano())
+				requestID = config.Generator()
 			}
 
 			// Store in context
-			ctx.Set("requestID", requestID)
+			ctx.Set(config.ContextKey, requestID)
 
 			// Add to response headers
-			ctx.Header().Set("X-Request-ID", requestID)
+			ctx.Header().Set(config.Header, requestID)
 
 			return next(ctx)
 		}
@@ -165,11 +240,13 @@ func CORSWithConfig(config CORSConfig) cosan.Middleware {
 
 // Helper functions
 
-func statusFromError(err error) string {
-	if err != nil {
-		return "500 ERROR"
+// responseStatus returns the status of the response, or the status the
+// error handler will write for err when nothing has been written yet.
+func responseStatus(ctx cosan.Context, err error) int {
+	if err != nil && ctx.BytesWritten() == 0 {
+		return cosan.ErrorStatus(err)
 	}
-	return "200 OK"
+	return ctx.StatusCode()
 }
 
 func contains(slice []string, item string) bool {
@@ -1,10 +1,15 @@
 package middleware_test
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
@@ -28,6 +33,32 @@ func TestLogger(t *testing.T) {
 	}
 }
 
+func TestLogger_UnmatchedRoutes(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	router := cosan.New()
+	router.Use(middleware.Logger())
+	router.GET("/users", func(ctx cosan.Context) error {
+		return ctx.String(200, "OK")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "/missing <not-found> 404 ") {
+		t.Errorf("Expected not-found label and 404, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "/users <method-not-allowed> 405 ") {
+		t.Errorf("Expected method-not-allowed label and 405, got %q", lines[1])
+	}
+}
+
 func TestRecovery(t *testing.T) {
 	router := cosan.New()
 	router.Use(middleware.Recovery())
@@ -50,6 +81,32 @@ func TestRecovery(t *testing.T) {
 	}
 }
 
+func TestRecoveryWithConfig(t *testing.T) {
+	router := cosan.New()
+	router.Use(middleware.RecoveryWithConfig(middleware.RecoveryConfig{
+		ResponseFunc: func(ctx cosan.Context, recovered interface{}) {
+			_ = ctx.HTML(500, fmt.Sprintf("<h1>Oops: %v</h1>", recovered))
+		},
+	}))
+	router.GET("/panic", func(ctx cosan.Context) error {
+		panic("test panic")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != 500 {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Expected HTML content type, got %q", ct)
+	}
+	if w.Body.String() != "<h1>Oops: test panic</h1>" {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+}
+
 func TestRequestID(t *testing.T) {
 	router := cosan.New()
 	router.Use(middleware.RequestID())
@@ -86,6 +143,39 @@ func TestRequestIDPreserve(t *testing.T) {
 	}
 }
 
+func TestRequestIDWithConfig(t *testing.T) {
+	router := cosan.New()
+	router.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		Header:     "X-Correlation-ID",
+		ContextKey: "correlationID",
+		Generator:  func() string { return "generated-id" },
+	}))
+	var stored interface{}
+	router.GET("/test", func(ctx cosan.Context) error {
+		stored = ctx.Get("correlationID")
+		return ctx.String(200, "OK")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	if w.Header().Get("X-Correlation-ID") != "generated-id" || stored != "generated-id" {
+		t.Errorf("Expected generated ID in header and context, got %q and %v", w.Header().Get("X-Correlation-ID"), stored)
+	}
+	if w.Header().Get("X-Request-ID") != "" {
+		t.Error("Expected no X-Request-ID header with a custom header name")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Correlation-ID", "incoming-id")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Header().Get("X-Correlation-ID") != "incoming-id" || stored != "incoming-id" {
+		t.Errorf("Expected incoming ID to be preserved, got %q and %v", w.Header().Get("X-Correlation-ID"), stored)
+	}
+}
+
 func TestCORS(t *testing.T) {
 	router := cosan.New()
 	router.Use(middleware.CORS())
@@ -265,3 +355,56 @@ func BenchmarkMiddlewareChain(b *testing.B) {
 		router.ServeHTTP(w, req)
 	}
 }
+
+// hijackRecorder is a ResponseRecorder whose connection can be hijacked.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn     net.Conn
+	hijacked bool
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	rw := bufio.NewReadWriter(bufio.NewReader(h.conn), bufio.NewWriter(h.conn))
+	return h.conn, rw, nil
+}
+
+func TestHijackThroughMiddlewareChain(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	var hookStatus int
+	router := cosan.New()
+	router.Use(middleware.Logger(), middleware.Compress(), middleware.RequestID())
+	router.AfterResponse(func(req *http.Request, statusCode int) {
+		hookStatus = statusCode
+	})
+	router.GET("/ws", func(ctx cosan.Context) error {
+		hj, ok := ctx.Response().(http.Hijacker)
+		if !ok {
+			t.Fatal("Wrapped response does not implement http.Hijacker")
+		}
+		conn, _, err := hj.Hijack()
+		if err != nil {
+			return err
+		}
+		conn.Close()
+		// Errors after an upgrade must not be written to the hijacked connection.
+		return fmt.Errorf("connection closed")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}
+	router.ServeHTTP(w, req)
+
+	if !w.hijacked {
+		t.Fatal("Expected the underlying writer to be hijacked")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected nothing written after hijack, got %q", w.Body.String())
+	}
+	if hookStatus != http.StatusSwitchingProtocols {
+		t.Errorf("Expected after-response hook status 101, got %d", hookStatus)
+	}
+}
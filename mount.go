@@ -0,0 +1,108 @@
+package cosan
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// mountMethods are the methods a mounted handler is registered for.
+var mountMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// MountOption configures a handler mounted with Mount.
+type MountOption func(*mountConfig)
+
+// mountConfig holds Mount options.
+type mountConfig struct {
+	keepPrefix bool
+}
+
+// KeepMountPrefix passes the full request path to a mounted handler
+// instead of stripping the mount prefix.
+func KeepMountPrefix() MountOption {
+	return func(c *mountConfig) {
+		c.keepPrefix = true
+	}
+}
+
+// Mount serves every request under prefix with handler, e.g. a file server,
+// a legacy mux, or another router. Like http.StripPrefix, the prefix is
+// removed from the request path before delegating, so a handler mounted at
+// /legacy sees /legacy/users as /users (and /legacy as /); pass
+// KeepMountPrefix to keep the full path. The original request is not
+// modified.
+//
+// Example:
+//
+//	router.Mount("/legacy", legacyMux)
+func (r *router) Mount(prefix string, handler http.Handler, opts ...MountOption) {
+	prefix = normalizePrefix(prefix)
+	mountRoutes(r.registerRoute, prefix, prefix, handler, opts)
+}
+
+// mountRoutes registers the mount handler for the prefix itself and
+// everything below it. stripped is the full path prefix removed from
+// requests, which differs from prefix inside groups.
+func mountRoutes(register func(method, pattern string, handler HandlerFunc, opts ...RouteOption), prefix, stripped string, handler http.Handler, opts []MountOption) {
+	var cfg mountConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	h := fromHTTPHandler(handler)
+	if !cfg.keepPrefix {
+		h = stripPrefixHandler(stripped, handler)
+	}
+
+	for _, method := range mountMethods {
+		register(method, joinPath(prefix, ""), h, untimed())
+		register(method, joinPath(prefix, "*path"), h, untimed())
+	}
+}
+
+// fromHTTPHandler adapts an http.Handler to a HandlerFunc.
+func fromHTTPHandler(h http.Handler) HandlerFunc {
+	return func(ctx Context) error {
+		h.ServeHTTP(ctx.Response(), ctx.Request())
+		return nil
+	}
+}
+
+// stripPrefixHandler serves h with prefix removed from a copy of the
+// request URL.
+func stripPrefixHandler(prefix string, h http.Handler) HandlerFunc {
+	return func(ctx Context) error {
+		req := ctx.Request()
+
+		u := new(url.URL)
+		*u = *req.URL
+		u.Path = stripPrefix(u.Path, prefix)
+		if u.RawPath != "" {
+			u.RawPath = stripPrefix(u.RawPath, prefix)
+		}
+
+		stripped := new(http.Request)
+		*stripped = *req
+		stripped.URL = u
+
+		h.ServeHTTP(ctx.Response(), stripped)
+		return nil
+	}
+}
+
+// stripPrefix removes prefix from p, keeping a leading slash.
+func stripPrefix(p, prefix string) string {
+	p = strings.TrimPrefix(p, prefix)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
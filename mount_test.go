@@ -0,0 +1,59 @@
+package cosan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// echoPath writes the path it receives.
+var echoPath = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(r.Method + " " + r.URL.Path))
+})
+
+func TestMount(t *testing.T) {
+	r := New()
+	r.Mount("/legacy", echoPath)
+	r.Mount("/raw/", echoPath, KeepMountPrefix())
+	r.Group("/api").Mount("v1", echoPath)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{"GET", "/legacy/users/42", "GET /users/42"},
+		{"POST", "/legacy/users", "POST /users"},
+		{"GET", "/legacy", "GET /"},
+		{"DELETE", "/raw/items/1", "DELETE /raw/items/1"},
+		{"GET", "/api/v1/status", "GET /status"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != 200 || w.Body.String() != tt.want {
+			t.Errorf("%s %s: expected 200 %q, got %d %q", tt.method, tt.path, tt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestMount_OriginalRequestUnchanged(t *testing.T) {
+	r := New()
+	var seen string
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			err := next(ctx)
+			seen = ctx.Request().URL.Path
+			return err
+		}
+	}))
+	r.Mount("/legacy", echoPath)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/legacy/users", nil))
+
+	if seen != "/legacy/users" {
+		t.Errorf("Expected middleware to see the original path, got %q", seen)
+	}
+}
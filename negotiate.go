@@ -0,0 +1,117 @@
+package cosan
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// msgPackContentType is the media type of MessagePack bodies.
+const msgPackContentType = "application/msgpack"
+
+// Negotiate writes v in the format preferred by the request's Accept
+// header: JSON, plus MessagePack and Protocol Buffers when their codecs are
+// configured. Without an Accept header JSON is used; when nothing offered
+// is acceptable it returns an *HTTPError with status 406.
+func (c *context) Negotiate(code int, v interface{}) error {
+	offers := make([]string, 0, 3)
+	offers = append(offers, "application/json")
+	if c.msgPackCodec() != nil {
+		offers = append(offers, msgPackContentType)
+	}
+	if c.protoCodec() != nil {
+		offers = append(offers, protobufContentType)
+	}
+
+	switch negotiateMediaType(c.req.Header.Get("Accept"), offers) {
+	case "application/json":
+		return c.JSON(code, v)
+	case msgPackContentType:
+		return c.MsgPack(code, v)
+	case protobufContentType:
+		return c.Protobuf(code, v)
+	}
+
+	return NewHTTPError(http.StatusNotAcceptable, http.StatusText(http.StatusNotAcceptable))
+}
+
+// negotiateMediaType returns the offer the Accept header weights highest,
+// or "" if none is acceptable. Ties keep the order of offers, and an empty
+// header accepts the first offer.
+func negotiateMediaType(accept string, offers []string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := acceptQuality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// acceptRange is one media range of an Accept header.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept splits an Accept header into media ranges with q-values.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if mediaType == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			} else {
+				q = 0
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	return ranges
+}
+
+// acceptQuality returns the q-value of the most specific range matching
+// the media type: an exact match beats type/*, which beats */*.
+func acceptQuality(ranges []acceptRange, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.mediaType == mediaType:
+			s = 2
+		case r.mediaType == mainType+"/*":
+			s = 1
+		case r.mediaType == "*/*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+
+	return q
+}
@@ -0,0 +1,76 @@
+package cosan
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	offers := []string{"application/json", "application/msgpack"}
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"no header", "", "application/json"},
+		{"exact", "application/msgpack", "application/msgpack"},
+		{"q-values", "application/json;q=0.5, application/msgpack", "application/msgpack"},
+		{"offer order on tie", "application/*", "application/json"},
+		{"specific beats wildcard", "application/*;q=0.1, application/msgpack;q=0.9", "application/msgpack"},
+		{"rejected", "application/json;q=0, */*;q=0.2", "application/msgpack"},
+		{"none acceptable", "text/html", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiateMediaType(tt.accept, offers); got != tt.want {
+				t.Errorf("negotiateMediaType(%q) = %q, want %q", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	r := New(WithMsgPackCodec(stubMsgPackCodec{}))
+	r.GET("/item", func(ctx Context) error {
+		return ctx.Negotiate(200, map[string]string{"name": "cosan"})
+	})
+
+	tests := []struct {
+		accept   string
+		wantType string
+		wantCode int
+	}{
+		{"", "application/json", 200},
+		{"application/msgpack, application/json;q=0.9", "application/msgpack", 200},
+		{"application/x-protobuf", "text/plain; charset=utf-8", 406},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/item", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("Accept %q: expected status %d, got %d", tt.accept, tt.wantCode, w.Code)
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.wantType {
+			t.Errorf("Accept %q: expected Content-Type %q, got %q", tt.accept, tt.wantType, got)
+		}
+	}
+}
+
+func TestNegotiateNotAcceptable(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "text/html")
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	var httpErr *HTTPError
+	if err := ctx.Negotiate(200, "x"); !errors.As(err, &httpErr) || httpErr.Code != 406 {
+		t.Errorf("Expected 406 HTTPError, got %v", err)
+	}
+}
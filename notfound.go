@@ -0,0 +1,260 @@
+package cosan
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// WithNotFoundJSON responds to unmatched requests with a 404 JSON body
+// {"error": message}, without writing a not-found handler.
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithNotFoundJSON("resource not found"))
+func WithNotFoundJSON(message string) Option {
+	body := map[string]string{"error": message}
+	return func(r *router) {
+		r.notFound = func(ctx Context) error {
+			return ctx.JSON(http.StatusNotFound, body)
+		}
+	}
+}
+
+// WithNotFoundText responds to unmatched requests with a 404 plain-text body.
+func WithNotFoundText(message string) Option {
+	return func(r *router) {
+		r.notFound = func(ctx Context) error {
+			return ctx.String(http.StatusNotFound, "%s", message)
+		}
+	}
+}
+
+// SetNotFoundHandler sets the handler for requests that match no route.
+// It runs inside the global middleware; errors it returns go to the error
+// handler. If not set, a plain "404 page not found" is returned.
+func (r *router) SetNotFoundHandler(handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notFound = handler
+	r.recompileUnmatched()
+}
+
+// NotFound sets the handler for requests that match no route; on the
+// router it is the same as SetNotFoundHandler.
+func (r *router) NotFound(handler HandlerFunc) {
+	r.SetNotFoundHandler(handler)
+}
+
+// prefixNotFound is a not-found handler scoped to a group prefix.
+type prefixNotFound struct {
+	prefix  string
+	handler HandlerFunc
+}
+
+// setPrefixNotFound sets the not-found handler for unmatched paths under
+// prefix, keeping the list ordered longest prefix first.
+func (r *router) setPrefixNotFound(prefix string, handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	defer r.recompileUnmatched()
+
+	for i := range r.prefixNotFound {
+		if r.prefixNotFound[i].prefix == prefix {
+			r.prefixNotFound[i].handler = handler
+			return
+		}
+	}
+
+	r.prefixNotFound = append(r.prefixNotFound, prefixNotFound{prefix: prefix, handler: handler})
+	sort.SliceStable(r.prefixNotFound, func(i, j int) bool {
+		return len(r.prefixNotFound[i].prefix) > len(r.prefixNotFound[j].prefix)
+	})
+}
+
+// unmatchedChains holds the handler chains, wrapped in the global
+// middleware, for requests that no route serves. They are built when the
+// router compiles and rebuilt when a not-found or fallback handler changes
+// afterwards, so requests only load them.
+type unmatchedChains struct {
+	fallback         HandlerFunc      // nil without a fallback
+	notFound         HandlerFunc      // the router's
+	prefixNotFound   []prefixNotFound // the groups', longest prefix first
+	methodNotAllowed HandlerFunc
+}
+
+// compileUnmatched builds the unmatched chains. r.mu must be held.
+func (r *router) compileUnmatched() {
+	wrap := func(handler HandlerFunc) HandlerFunc {
+		if handler == nil {
+			handler = notFoundHandler
+		}
+		for i := len(r.middleware) - 1; i >= 0; i-- {
+			handler = r.middleware[i].Process(handler)
+		}
+		return handler
+	}
+
+	chains := &unmatchedChains{
+		notFound:         wrap(r.notFound),
+		prefixNotFound:   make([]prefixNotFound, len(r.prefixNotFound)),
+		methodNotAllowed: wrap(methodNotAllowedHandler),
+	}
+	if r.fallback != nil {
+		chains.fallback = wrap(r.fallback)
+	}
+	for i, nf := range r.prefixNotFound {
+		chains.prefixNotFound[i] = prefixNotFound{prefix: nf.prefix, handler: wrap(nf.handler)}
+	}
+
+	r.unmatched.Store(chains)
+}
+
+// recompileUnmatched rebuilds the unmatched chains of a compiled router
+// after a handler changes. r.mu must be held.
+func (r *router) recompileUnmatched() {
+	if r.compiled {
+		r.compileUnmatched()
+	}
+}
+
+// notFoundFor returns the not-found chain of the longest group prefix
+// containing path, falling back to the router's.
+func (c *unmatchedChains) notFoundFor(path string) HandlerFunc {
+	for _, nf := range c.prefixNotFound {
+		if path == nf.prefix || strings.HasPrefix(path, nf.prefix+"/") {
+			return nf.handler
+		}
+	}
+	return c.notFound
+}
+
+// Route labels reported by Context.RoutePattern for requests that no route
+// served, so logs and metrics keyed by pattern stay bounded no matter
+// which paths clients request.
+const (
+	NotFoundLabel             = "<not-found>"
+	MethodNotAllowedLabel     = "<method-not-allowed>"
+	FallbackLabel             = "<fallback>"
+	UnsupportedMediaTypeLabel = "<unsupported-media-type>"
+)
+
+var (
+	notFoundRoute             = &route{pattern: NotFoundLabel}
+	methodNotAllowedRoute     = &route{pattern: MethodNotAllowedLabel}
+	fallbackRoute             = &route{pattern: FallbackLabel}
+	unsupportedMediaTypeRoute = &route{pattern: UnsupportedMediaTypeLabel}
+)
+
+// Fallback sets a handler for every request that matches no route, e.g. to
+// forward it to an upstream server. It replaces the 404 and 405 responses
+// and any not-found handlers; explicit routes always take precedence. Like
+// a route handler, it runs inside the global middleware and its errors go
+// to the error handler. A nil handler removes the fallback.
+//
+// Example:
+//
+//	proxy := httputil.NewSingleHostReverseProxy(upstream)
+//	router.Fallback(func(ctx cosan.Context) error {
+//		proxy.ServeHTTP(ctx.Response(), ctx.Request())
+//		return nil
+//	})
+func (r *router) Fallback(handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = handler
+	r.recompileUnmatched()
+}
+
+// handleNotFound responds to a request that matched no route with the
+// fallback handler if one is set; otherwise 405 with an Allow header when
+// path is routed for other methods, and 404 if not. path is the routing
+// path, which also selects the group not-found handler. The response runs
+// through the global middleware and after-response hooks, so they observe
+// the real status.
+func (r *router) handleNotFound(w http.ResponseWriter, req *http.Request, path string) {
+	chains := r.unmatched.Load()
+	rt, handler := fallbackRoute, chains.fallback
+	var allowed []string
+	if handler == nil {
+		rt, handler = notFoundRoute, chains.notFoundFor(path)
+		if allowed = r.allowedMethods(req.Method, path); len(allowed) > 0 {
+			rt, handler = methodNotAllowedRoute, chains.methodNotAllowed
+		}
+	}
+
+	ctx := acquireContextFrom(r.contextPool, w, req)
+	ctx.router = r
+	ctx.route = rt
+	ctx.allowed = allowed
+	defer releaseContext(ctx)
+
+	recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	ctx.res = recorder
+	ctx.recorder = recorder
+
+	hctx := r.handlerContext(ctx)
+	defer r.releaseHandlerContext(hctx)
+
+	if err := r.execute(handler, hctx); err != nil {
+		r.handleError(hctx, err)
+	}
+
+	r.executeAfterHooks(req, recorder.statusCode)
+}
+
+// MethodsFor returns the sorted HTTP methods with a route matching the
+// concrete path, including through parameter and wildcard patterns, with
+// HEAD wherever GET matches. It is the set advertised in the Allow header of
+// 405 responses.
+//
+// Example:
+//
+//	router.MethodsFor("/users/123") // [DELETE GET HEAD PUT]
+func (r *router) MethodsFor(path string) []string {
+	return r.allowedMethods("", path)
+}
+
+// allowedMethods returns the sorted methods, other than method, that have
+// a route matching path, counting HEAD as served by GET routes.
+func (r *router) allowedMethods(method, path string) []string {
+	r.mu.RLock()
+	methods := r.methods
+	r.mu.RUnlock()
+
+	var allowed []string
+	for _, m := range methods {
+		if m == method {
+			continue
+		}
+		if _, _, found := r.matcher.Match(m, path); found {
+			allowed = append(allowed, m)
+		}
+	}
+	// GET routes also serve HEAD
+	if method != http.MethodHead && slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// notFoundHandler writes the default "404 page not found" response.
+func notFoundHandler(ctx Context) error {
+	http.NotFound(ctx.Response(), ctx.Request())
+	return nil
+}
+
+// methodNotAllowedHandler writes a 405 response listing the methods
+// handleNotFound found for the path.
+func methodNotAllowedHandler(ctx Context) error {
+	if c, ok := unwrapContext(ctx); ok {
+		ctx.Header().Set("Allow", strings.Join(c.allowed, ", "))
+	}
+	http.Error(ctx.Response(), http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	return nil
+}
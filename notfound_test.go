@@ -0,0 +1,247 @@
+package cosan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestNotFoundOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantType string
+		wantBody string
+		setup    func(Router)
+	}{
+		{"default", nil, "text/plain; charset=utf-8", "404 page not found\n", nil},
+		{"json", []Option{WithNotFoundJSON("resource not found")}, "application/json", "{\"error\":\"resource not found\"}\n", nil},
+		{"text", []Option{WithNotFoundText("nothing here")}, "text/plain; charset=utf-8", "nothing here", nil},
+		{"handler", nil, "text/html; charset=utf-8", "<h1>gone</h1>", func(r Router) {
+			r.SetNotFoundHandler(func(ctx Context) error {
+				return ctx.HTML(404, "<h1>gone</h1>")
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.opts...)
+			r.GET("/exists", func(ctx Context) error { return nil })
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+
+			if w.Code != 404 {
+				t.Errorf("Expected status 404, got %d", w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.wantType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.wantType, ct)
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGroupNotFound(t *testing.T) {
+	r := New(WithNotFoundText("global 404"))
+	api := r.Group("/api")
+	api.GET("/users", func(ctx Context) error { return nil })
+	api.NotFound(func(ctx Context) error {
+		return ctx.JSON(404, map[string]string{"error": "api 404"})
+	})
+	api.Group("/v2").NotFound(func(ctx Context) error {
+		return ctx.String(404, "v2 404")
+	})
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/api/missing", "{\"error\":\"api 404\"}\n"},
+		{"/api", "{\"error\":\"api 404\"}\n"},
+		{"/api/v2/missing", "v2 404"},
+		{"/apix/missing", "global 404"},
+		{"/missing", "global 404"},
+		// Routing keeps %2F inside its segment, so neither group contains it
+		{"/api%2Fv2/missing", "global 404"},
+		{"/api/v2%2Fmissing", "{\"error\":\"api 404\"}\n"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != 404 || w.Body.String() != tt.body {
+			t.Errorf("%s: expected 404 %q, got %d %q", tt.path, tt.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	r := New()
+	var patterns []string
+	var statuses []int
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			patterns = append(patterns, ctx.RoutePattern())
+			return next(ctx)
+		}
+	}))
+	r.AfterResponse(func(req *http.Request, statusCode int) {
+		statuses = append(statuses, statusCode)
+	})
+	r.GET("/users/:id", func(ctx Context) error { return nil })
+	r.DELETE("/users/:id", func(ctx Context) error { return nil })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users/1", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+	if got := w.Header().Get("Allow"); got != "DELETE, GET, HEAD" {
+		t.Errorf("Expected Allow 'DELETE, GET, HEAD', got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+
+	wantPatterns := []string{MethodNotAllowedLabel, NotFoundLabel}
+	wantStatuses := []int{http.StatusMethodNotAllowed, http.StatusNotFound}
+	if !reflect.DeepEqual(patterns, wantPatterns) {
+		t.Errorf("Expected middleware to see %v, got %v", wantPatterns, patterns)
+	}
+	if !reflect.DeepEqual(statuses, wantStatuses) {
+		t.Errorf("Expected after-response hooks to see %v, got %v", wantStatuses, statuses)
+	}
+}
+
+func TestMethodsFor(t *testing.T) {
+	r := New()
+	handler := func(ctx Context) error { return nil }
+	r.GET("/users/:id", handler)
+	r.PUT("/users/:id", handler)
+	r.DELETE("/users/:id", handler)
+	r.POST("/users", handler)
+	r.GET("/files/*path", handler)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/users/123", []string{"DELETE", "GET", "HEAD", "PUT"}},
+		{"/users", []string{"POST"}},
+		{"/files/a/b", []string{"GET", "HEAD"}},
+		{"/missing", nil},
+	}
+
+	for _, tt := range tests {
+		if got := r.MethodsFor(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MethodsFor(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if got := r.Group("/api").MethodsFor("/users/123"); len(got) != 4 {
+		t.Errorf("Expected group to delegate, got %v", got)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	r := New(WithNotFoundText("not found"))
+	var seen []string
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			seen = append(seen, ctx.RoutePattern())
+			return next(ctx)
+		}
+	}))
+	r.GET("/users/:id", func(ctx Context) error {
+		return ctx.String(200, "user %s", ctx.Param("id"))
+	})
+	r.Group("/api").NotFound(func(ctx Context) error {
+		return ctx.String(404, "api 404")
+	})
+	r.Fallback(func(ctx Context) error {
+		return ctx.String(http.StatusBadGateway, "upstream %s %s", ctx.Request().Method, ctx.Request().URL.Path)
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{"GET", "/users/1", 200, "user 1"},
+		{"GET", "/anything/else", http.StatusBadGateway, "upstream GET /anything/else"},
+		{"POST", "/users/1", http.StatusBadGateway, "upstream POST /users/1"},
+		{"GET", "/api/missing", http.StatusBadGateway, "upstream GET /api/missing"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.code, tt.body, w.Code, w.Body.String())
+		}
+	}
+
+	want := []string{"/users/:id", FallbackLabel, FallbackLabel, FallbackLabel}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("Expected middleware to see %v, got %v", want, seen)
+	}
+
+	r.Fallback(nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/anything/else", nil))
+	if w.Code != 404 || w.Body.String() != "not found" {
+		t.Errorf("Expected the 404 handler after removing the fallback, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestUnmatchedChainsCompiledOnce(t *testing.T) {
+	processed := 0
+	r := New()
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		processed++
+		return next
+	}))
+	r.GET("/users", func(ctx Context) error { return ctx.String(200, "users") })
+	r.Group("/api").NotFound(func(ctx Context) error { return ctx.String(404, "api") })
+
+	for _, path := range []string{"/missing", "/api/missing", "/other"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users", nil))
+	if w.Code != 405 || w.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("Expected 405 with Allow GET, HEAD, got %d %q", w.Code, w.Header().Get("Allow"))
+	}
+
+	// One route, the 415, router and group not-found, and 405 chains
+	compiled := processed
+	if compiled != 5 {
+		t.Errorf("Expected 5 chains built at compile time, got %d", compiled)
+	}
+	for i := 0; i < 3; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+	}
+	if processed != compiled {
+		t.Errorf("Expected unmatched requests to reuse the compiled chains, got %d more", processed-compiled)
+	}
+
+	r.Fallback(func(ctx Context) error { return ctx.String(200, "fallback") })
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	if w.Body.String() != "fallback" {
+		t.Errorf("Expected a fallback set after compiling to apply, got %q", w.Body.String())
+	}
+}
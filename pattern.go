@@ -0,0 +1,45 @@
+package cosan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validatePattern checks the segments of a pattern: only the final one
+// may be empty (a trailing slash), :param and *wildcard names must be
+// non-empty, a wildcard must be the final segment, no segment may mix ':'
+// and '*', and only the final parameter may be optional (:name?).
+func validatePattern(pattern string) error {
+	segments := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	for i, segment := range segments {
+		if segment == "" && i != len(segments)-1 {
+			return fmt.Errorf("%w: %s has an empty segment (duplicate slash)", ErrInvalidPattern, pattern)
+		}
+		if strings.Contains(segment, ":") && strings.Contains(segment, "*") {
+			return fmt.Errorf("%w: segment %q of %s mixes ':' and '*'", ErrInvalidPattern, segment, pattern)
+		}
+
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name := segment[1:]
+			if optional := strings.TrimSuffix(name, "?"); optional != name {
+				if i != len(segments)-1 {
+					return fmt.Errorf("%w: optional parameter %q of %s must be the final segment", ErrInvalidPattern, segment, pattern)
+				}
+				name = optional
+			}
+			if name == "" || strings.ContainsAny(name, ":?") {
+				return fmt.Errorf("%w: segment %q of %s has an empty or invalid parameter name", ErrInvalidPattern, segment, pattern)
+			}
+		case strings.HasPrefix(segment, "*"):
+			if name := segment[1:]; name == "" || strings.Contains(name, "*") {
+				return fmt.Errorf("%w: segment %q of %s has an empty or invalid wildcard name", ErrInvalidPattern, segment, pattern)
+			}
+			if i != len(segments)-1 {
+				return fmt.Errorf("%w: wildcard %q of %s must be the final segment", ErrInvalidPattern, segment, pattern)
+			}
+		}
+	}
+
+	return nil
+}
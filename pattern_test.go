@@ -0,0 +1,56 @@
+package cosan
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatePattern(t *testing.T) {
+	valid := []string{"/", "/users", "/users/:id", "/users/:id/posts/:postID", "/files/*path", "/a:b/c", "/posts/:id/:slug?", "/users/"}
+	for _, pattern := range valid {
+		if err := validatePattern(pattern); err != nil {
+			t.Errorf("%s: unexpected error %v", pattern, err)
+		}
+	}
+
+	invalid := []struct {
+		pattern string
+		segment string
+	}{
+		{"/users/:", `":"`},
+		{"/files/*", `"*"`},
+		{"/a/**/b", `"**"`},
+		{"/a/*mid/b", `"*mid"`},
+		{"/a/:id*", `":id*"`},
+		{"/a/*rest:x", `"*rest:x"`},
+		{"/a/:x?/b", `":x?"`},
+		{"/a/:?", `":?"`},
+		{"/a//b", "empty segment"},
+		{"//a", "empty segment"},
+	}
+	for _, tt := range invalid {
+		err := validatePattern(tt.pattern)
+		if !errors.Is(err, ErrInvalidPattern) {
+			t.Errorf("%s: expected ErrInvalidPattern, got %v", tt.pattern, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.segment) {
+			t.Errorf("%s: expected error to point at %s, got %q", tt.pattern, tt.segment, err)
+		}
+	}
+}
+
+func TestRegisterInvalidPattern(t *testing.T) {
+	m := newRadixMatcher()
+	if err := m.Register("GET", "/a/*mid/b", func(ctx Context) error { return nil }); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("Expected ErrInvalidPattern from Register, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected router registration to panic on an invalid pattern")
+		}
+	}()
+	New().GET("/users/:", func(ctx Context) error { return nil })
+}
@@ -5,19 +5,52 @@ import (
 	"sync"
 )
 
-// contextPool manages the recycling of Context instances to reduce allocations
-var contextPool = sync.Pool{
-	New: func() interface{} {
+// DefaultContextValueCapacity is the initial capacity of a pooled context's
+// value map, as set by WithContextValueCapacity.
+const DefaultContextValueCapacity = 4
+
+// WithContextValueCapacity sizes the value map of each pooled context for n
+// entries, so middleware stacks that Set many values (claims, request ID,
+// tenant, trace span, ...) don't grow the map on every request. Values
+// below 1 keep DefaultContextValueCapacity.
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithContextValueCapacity(16))
+func WithContextValueCapacity(n int) Option {
+	return func(r *router) {
+		if n < 1 {
+			n = DefaultContextValueCapacity
+		}
+		r.contextPool = newContextPool(n)
+	}
+}
+
+// newContextPool returns a pool of contexts whose value maps are created
+// with the given capacity.
+func newContextPool(valueCapacity int) *sync.Pool {
+	pool := &sync.Pool{}
+	pool.New = func() interface{} {
 		return &context{
 			params: make(map[string]string, 4),
-			values: make(map[string]interface{}, 4),
+			values: make(map[string]interface{}, valueCapacity),
+			pool:   pool,
 		}
-	},
+	}
+	return pool
 }
 
-// acquireContext gets a Context from the pool
+// contextPool manages the recycling of Context instances to reduce allocations
+var contextPool = newContextPool(DefaultContextValueCapacity)
+
+// acquireContext gets a Context from the shared pool
 func acquireContext(w http.ResponseWriter, r *http.Request) *context {
-	ctx := contextPool.Get().(*context)
+	return acquireContextFrom(contextPool, w, r)
+}
+
+// acquireContextFrom gets a Context from pool
+func acquireContextFrom(pool *sync.Pool, w http.ResponseWriter, r *http.Request) *context {
+	ctx := pool.Get().(*context)
 	ctx.req = r
 	ctx.res = w
 	return ctx
@@ -36,7 +69,18 @@ func releaseContext(ctx *context) {
 	// Reset fields
 	ctx.req = nil
 	ctx.res = nil
+	ctx.router = nil
+	ctx.recorder = nil
+	ctx.route = nil
+	ctx.logger = nil
+	ctx.query = nil
+	ctx.body = nil
+	ctx.bodyRead = false
+	ctx.formErr = nil
+	ctx.formParsed = false
+	ctx.allowed = nil
+	ctx.timedOut = nil
 
-	// Return to pool
-	contextPool.Put(ctx)
+	// Return to the pool it came from
+	ctx.pool.Put(ctx)
 }
@@ -2,6 +2,7 @@ package cosan
 
 import (
 	"net/http/httptest"
+	"strconv"
 	"testing"
 )
 
@@ -71,6 +72,24 @@ func TestContextPool_Reuse(t *testing.T) {
 	releaseContext(ctx2)
 }
 
+func TestContextPool_QueryCacheReset(t *testing.T) {
+	ctx1 := acquireContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/a?q=first", nil))
+	if got := ctx1.Query("q"); got != "first" {
+		t.Fatalf("Expected q=first, got %q", got)
+	}
+	releaseContext(ctx1)
+
+	if ctx1.query != nil {
+		t.Error("Expected cached query to be cleared on release")
+	}
+
+	ctx2 := acquireContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/b?q=second", nil))
+	defer releaseContext(ctx2)
+	if got := ctx2.Query("q"); got != "second" {
+		t.Errorf("Expected q=second, got %q", got)
+	}
+}
+
 func TestContextPool_ConcurrentUsage(t *testing.T) {
 	const concurrency = 100
 
@@ -153,3 +172,63 @@ func BenchmarkContextPool_NoPool(b *testing.B) {
 		_ = ctx
 	}
 }
+
+func TestWithContextValueCapacity(t *testing.T) {
+	r := New(WithContextValueCapacity(16)).(*router)
+	if r.contextPool == contextPool {
+		t.Fatal("Expected a router-specific context pool")
+	}
+
+	r.GET("/", func(ctx Context) error {
+		ctx.Set("user", "alice")
+		return nil
+	})
+	for i := 0; i < 2; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}
+
+	ctx := acquireContextFrom(r.contextPool, httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	defer releaseContext(ctx)
+	if ctx.pool != r.contextPool {
+		t.Error("Expected context to return to the router's pool")
+	}
+	if len(ctx.values) != 0 {
+		t.Errorf("Expected cleared values, got %v", ctx.values)
+	}
+}
+
+// benchmarkContextValues serves requests whose middleware sets ten values.
+func benchmarkContextValues(b *testing.B, opts ...Option) {
+	keys := make([]string, 10)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+
+	r := New(opts...)
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			for _, key := range keys {
+				ctx.Set(key, key)
+			}
+			return next(ctx)
+		}
+	}))
+	r.GET("/", func(ctx Context) error { return nil })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkContextValues_DefaultCapacity(b *testing.B) {
+	benchmarkContextValues(b)
+}
+
+func BenchmarkContextValues_Capacity16(b *testing.B) {
+	benchmarkContextValues(b, WithContextValueCapacity(16))
+}
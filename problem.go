@@ -0,0 +1,62 @@
+package cosan
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem documents.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details document.
+type Problem struct {
+	// Type is a URI identifying the problem type. Defaults to "about:blank".
+	Type string `json:"type"`
+
+	// Title is a short, human-readable summary of the problem type.
+	Title string `json:"title"`
+
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+
+	// Detail is a human-readable explanation specific to this occurrence.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance is a URI identifying this occurrence, by default the request path.
+	Instance string `json:"instance,omitempty"`
+}
+
+// Problem writes an application/problem+json response. An empty title
+// defaults to the status text, and the instance is the request path.
+func (c *context) Problem(status int, title, detail string) error {
+	if title == "" {
+		title = http.StatusText(status)
+	}
+
+	return c.writeJSON(status, ProblemContentType, &Problem{
+		Type:     "about:blank",
+		Title:    title,
+		Status:   status,
+		Detail:   detail,
+		Instance: c.req.URL.Path,
+	})
+}
+
+// ProblemErrorHandler is an ErrorHandler that renders errors as RFC 7807
+// problem documents. An *HTTPError keeps its status code and message;
+// any other error becomes a 500 without details, so internals never leak
+// to clients.
+//
+// Example:
+//
+//	api := router.Group("/api")
+//	api.SetErrorHandler(cosan.ProblemErrorHandler)
+func ProblemErrorHandler(ctx Context, err error) {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		_ = ctx.Problem(httpErr.Code, "", httpErr.Message)
+		return
+	}
+
+	_ = ctx.Problem(http.StatusInternalServerError, "", "")
+}
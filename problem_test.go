@@ -0,0 +1,78 @@
+package cosan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_Problem(t *testing.T) {
+	r := New()
+	r.GET("/orders/:id", func(ctx Context) error {
+		return ctx.Problem(409, "Order locked", "order is being processed")
+	})
+
+	req := httptest.NewRequest("GET", "/orders/7", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 409 {
+		t.Errorf("Expected status 409, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Expected Content-Type %q, got %q", ProblemContentType, ct)
+	}
+
+	var p Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("Invalid problem body: %v", err)
+	}
+	want := Problem{Type: "about:blank", Title: "Order locked", Status: 409, Detail: "order is being processed", Instance: "/orders/7"}
+	if p != want {
+		t.Errorf("Expected %+v, got %+v", want, p)
+	}
+}
+
+func TestProblemErrorHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantTitle  string
+		wantDetail string
+	}{
+		{"http error", NewHTTPError(404, "user not found"), 404, "Not Found", "user not found"},
+		{"wrapped http error", fmt.Errorf("lookup: %w", NewHTTPError(403, "forbidden")), 403, "Forbidden", "forbidden"},
+		{"plain error", errors.New("database password is hunter2"), 500, "Internal Server Error", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			r.SetErrorHandler(ProblemErrorHandler)
+			r.GET("/users", func(ctx Context) error {
+				return tt.err
+			})
+
+			req := httptest.NewRequest("GET", "/users", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var p Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatalf("Invalid problem body: %v", err)
+			}
+			if w.Code != tt.wantStatus || p.Status != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d (body %d)", tt.wantStatus, w.Code, p.Status)
+			}
+			if p.Title != tt.wantTitle || p.Detail != tt.wantDetail {
+				t.Errorf("Expected %q/%q, got %q/%q", tt.wantTitle, tt.wantDetail, p.Title, p.Detail)
+			}
+			if p.Instance != "/users" {
+				t.Errorf("Expected instance /users, got %q", p.Instance)
+			}
+		})
+	}
+}
@@ -169,3 +169,146 @@ func TestHighConcurrencyLoad(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestConcurrentDynamicRegistration tests registering routes while serving
+func TestConcurrentDynamicRegistration(t *testing.T) {
+	r := New(WithDynamicRoutes(true))
+	r.GET("/base", func(ctx Context) error {
+		return ctx.String(200, "base")
+	})
+
+	const goroutines = 50
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines * 2)
+
+	for i := 0; i < goroutines; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			pattern := "/dyn" + string(rune('a'+i%26)) + string(rune('a'+(i/26)%26))
+			r.GET(pattern, func(ctx Context) error {
+				return ctx.String(200, "OK")
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", "/base", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != 200 {
+				t.Errorf("Expected status 200, got %d", w.Code)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// TestConcurrentFallback tests setting the fallback while serving
+func TestConcurrentFallback(t *testing.T) {
+	r := New()
+	r.GET("/hello", func(ctx Context) error {
+		return ctx.String(200, "Hello World")
+	})
+
+	const goroutines = 50
+	const requestsPerGoroutine = 100
+
+	stop := make(chan struct{})
+	setterDone := make(chan struct{})
+	go func() {
+		defer close(setterDone)
+		for j := 0; ; j++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if j%2 == 0 {
+				r.Fallback(func(ctx Context) error {
+					return ctx.String(200, "fallback")
+				})
+			} else {
+				r.Fallback(nil)
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requestsPerGoroutine; j++ {
+				req := httptest.NewRequest("GET", "/missing", nil)
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				if w.Code != 200 && w.Code != 404 {
+					t.Errorf("Expected status 200 or 404, got %d", w.Code)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(stop)
+	<-setterDone
+}
+
+// TestConcurrentGroupNotFound tests setting group not-found handlers while
+// serving
+func TestConcurrentGroupNotFound(t *testing.T) {
+	r := New()
+	api := r.Group("/api")
+	api.GET("/users", func(ctx Context) error {
+		return ctx.String(200, "users")
+	})
+
+	const goroutines = 50
+	const requestsPerGoroutine = 100
+
+	stop := make(chan struct{})
+	setterDone := make(chan struct{})
+	go func() {
+		defer close(setterDone)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			api.NotFound(func(ctx Context) error {
+				return ctx.String(404, "api 404")
+			})
+			r.SetNotFoundHandler(func(ctx Context) error {
+				return ctx.String(404, "404")
+			})
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requestsPerGoroutine; j++ {
+				req := httptest.NewRequest("GET", "/api/missing", nil)
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				if w.Code != 404 {
+					t.Errorf("Expected status 404, got %d", w.Code)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(stop)
+	<-setterDone
+}
@@ -1,6 +1,8 @@
 package cosan
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -10,6 +12,10 @@ type radixMatcher struct {
 	mu       sync.RWMutex
 	trees    map[string]*radixNode // One tree per HTTP method
 	compiled bool
+	dynamic  bool // Allow registration after Compile; Match then takes a read lock
+	count    int  // Registration counter for ordering Routes()
+
+	wildcardEmptyMatch bool // A wildcard also matches its bare prefix (see WithWildcardEmptyMatch)
 }
 
 // radixNode represents a node in the radix tree.
@@ -41,29 +47,94 @@ func newRadixMatcher() *radixMatcher {
 
 // Register adds a route to the radix tree (implements Matcher interface).
 func (m *radixMatcher) Register(method, pattern string, handler HandlerFunc) error {
+	return m.insert(&route{
+		method:  method,
+		pattern: pattern,
+		handler: handler,
+	})
+}
+
+// insert adds an existing route to the radix tree.
+func (m *radixMatcher) insert(r *route) error {
+	if err := validatePattern(r.pattern); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.compiled {
+	if m.compiled && !m.dynamic {
 		return ErrRouterAlreadyCompiled
 	}
 
-	// Create route wrapper
-	r := &route{
-		method:  method,
-		pattern: pattern,
-		handler: handler,
-	}
-
 	// Get or create tree for this method
-	tree := m.trees[method]
+	tree := m.trees[r.method]
 	if tree == nil {
 		tree = &radixNode{nType: staticNode}
-		m.trees[method] = tree
+		m.trees[r.method] = tree
 	}
 
 	// Insert route into tree
-	return m.insertRoute(tree, pattern, r)
+	if err := m.insertRoute(tree, r.pattern, r); err != nil {
+		return err
+	}
+
+	r.seq = m.count
+	m.count++
+
+	// Keep a compiled tree ordered when inserting dynamically
+	if m.compiled {
+		sortByPriority(tree)
+	}
+
+	return nil
+}
+
+// Routes returns all registered routes in registration order.
+func (m *radixMatcher) Routes() []Route {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var routes []*route
+	seen := make(map[*route]bool)
+	for _, tree := range m.trees {
+		routes = collectRoutes(tree, routes, seen)
+	}
+
+	return sortedRoutes(routes)
+}
+
+// collectRoutes appends every route stored in the subtree. A route with an
+// optional parameter is stored at two nodes but collected once.
+func collectRoutes(node *radixNode, routes []*route, seen map[*route]bool) []*route {
+	if node.route != nil && !seen[node.route] {
+		seen[node.route] = true
+		routes = append(routes, node.route)
+	}
+	for _, child := range node.children {
+		routes = collectRoutes(child, routes, seen)
+	}
+	if node.wildcard != nil {
+		routes = collectRoutes(node.wildcard, routes, seen)
+	}
+
+	return routes
+}
+
+// setDynamic allows registration after Compile.
+func (m *radixMatcher) setDynamic(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dynamic = enabled
+}
+
+// setWildcardEmptyMatch controls whether wildcards match their bare prefix.
+func (m *radixMatcher) setWildcardEmptyMatch(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.wildcardEmptyMatch = enabled
 }
 
 // insertRoute inserts a route into the radix tree.
@@ -85,8 +156,11 @@ func (m *radixMatcher) insertRoute(node *radixNode, pattern string, r *route) er
 
 	// Determine segment type
 	if strings.HasPrefix(segment, ":") {
-		// Named parameter
+		// Named parameter; a trailing '?' makes the final one optional
 		paramName := segment[1:]
+		if optional := strings.TrimSuffix(paramName, "?"); optional != paramName {
+			return m.insertOptionalParam(node, optional, r)
+		}
 		return m.insertParam(node, paramName, remaining, r)
 	} else if strings.HasPrefix(segment, "*") {
 		// Wildcard parameter
@@ -99,17 +173,14 @@ func (m *radixMatcher) insertRoute(node *radixNode, pattern string, r *route) er
 }
 
 // insertStatic inserts a static path segment.
+// Static nodes always hold a whole segment: splitting on a shared prefix
+// (e.g. "res1" and "res10") would make the longer segment unreachable,
+// since search only descends at segment boundaries.
 func (m *radixMatcher) insertStatic(node *radixNode, segment, remaining string, r *route) error {
-	// Look for existing child with matching prefix
+	// Look for existing child for the same segment
 	for _, child := range node.children {
-		if child.nType == staticNode && strings.HasPrefix(segment, child.path) {
-			if len(child.path) == len(segment) {
-				// Exact match - continue with remaining
-				return m.insertRoute(child, remaining, r)
-			}
-			// Partial match - continue with rest of segment
-			newSegment := segment[len(child.path):]
-			return m.insertRoute(child, newSegment+"/"+remaining, r)
+		if child.nType == staticNode && child.path == segment {
+			return m.insertRoute(child, remaining, r)
 		}
 	}
 
@@ -125,12 +196,19 @@ func (m *radixMatcher) insertStatic(node *radixNode, segment, remaining string,
 }
 
 // insertParam inserts a parameter node.
+// A position holds at most one parameter: sibling params with different
+// names (/users/:id and /users/:userId) would capture nondeterministically.
 func (m *radixMatcher) insertParam(node *radixNode, paramName, remaining string, r *route) error {
-	// Look for existing param node with same name
+	// Look for existing param node at this position
 	for _, child := range node.children {
-		if child.nType == paramNode && child.paramName == paramName {
-			return m.insertRoute(child, remaining, r)
+		if child.nType != paramNode {
+			continue
 		}
+		if child.paramName != paramName {
+			return fmt.Errorf("%w: parameter :%s in %s conflicts with :%s at the same position",
+				ErrConflictingRoutes, paramName, r.pattern, child.paramName)
+		}
+		return m.insertRoute(child, remaining, r)
 	}
 
 	// Create new param node
@@ -144,6 +222,21 @@ func (m *radixMatcher) insertParam(node *radixNode, paramName, remaining string,
 	return m.insertRoute(newNode, remaining, r)
 }
 
+// insertOptionalParam inserts a final optional parameter: the route is
+// stored both at the parameter node and at node itself, where the
+// parameter is absent.
+func (m *radixMatcher) insertOptionalParam(node *radixNode, paramName string, r *route) error {
+	if node.route != nil {
+		return ErrConflictingRoutes
+	}
+	if err := m.insertParam(node, paramName, "", r); err != nil {
+		return err
+	}
+	node.route = r
+
+	return nil
+}
+
 // insertWildcard inserts a wildcard node.
 func (m *radixMatcher) insertWildcard(node *radixNode, paramName string, r *route) error {
 	if node.wildcard != nil {
@@ -160,6 +253,16 @@ func (m *radixMatcher) insertWildcard(node *radixNode, paramName string, r *rout
 	return nil
 }
 
+// reset drops every route and clears the compiled state.
+func (m *radixMatcher) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.trees = make(map[string]*radixNode)
+	m.compiled = false
+	m.count = 0
+}
+
 // Compile prepares the matcher for use.
 func (m *radixMatcher) Compile() error {
 	m.mu.Lock()
@@ -184,14 +287,11 @@ func sortByPriority(node *radixNode) {
 		return
 	}
 
-	// Sort children: static first, then params
-	for i := 0; i < len(node.children); i++ {
-		for j := i + 1; j < len(node.children); j++ {
-			if node.children[j].priority > node.children[i].priority {
-				node.children[i], node.children[j] = node.children[j], node.children[i]
-			}
-		}
-	}
+	// Sort children: static first, then params. The sort is stable so
+	// siblings of equal priority keep their registration order.
+	sort.SliceStable(node.children, func(i, j int) bool {
+		return node.children[i].priority > node.children[j].priority
+	})
 
 	// Recursively sort children
 	for _, child := range node.children {
@@ -200,9 +300,13 @@ func sortByPriority(node *radixNode) {
 }
 
 // Match finds a route matching the given method and path.
-func (m *radixMatcher) Match(method, path string) (*Route, map[string]string, bool) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+// The compiled tree is immutable unless dynamic routes are enabled,
+// so only dynamic matchers pay for the read lock.
+func (m *radixMatcher) Match(method, path string) (Route, map[string]string, bool) {
+	if m.dynamic {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+	}
 
 	tree := m.trees[method]
 	if tree == nil {
@@ -213,20 +317,30 @@ func (m *radixMatcher) Match(method, path string) (*Route, map[string]string, bo
 	path = strings.TrimPrefix(path, "/")
 
 	params := make(map[string]string)
-	route := search(tree, path, params)
+	route := search(tree, path, params, true, m.wildcardEmptyMatch)
 
 	if route != nil {
-		var r Route = route
-		return &r, params, true
+		return route, params, true
 	}
 
 	return nil, nil, false
 }
 
 // search recursively searches for a matching route.
-func search(node *radixNode, path string, params map[string]string) *route {
-	// If path is empty, return route at this node
+//
+// A wildcard captures everything after its prefix and the following slash,
+// so /static/*path matches /static/ with an empty capture. slash reports
+// whether the segment consumed before reaching node was followed by a
+// slash; without one (/static), the wildcard only matches when emptyMatch
+// is set.
+func search(node *radixNode, path string, params map[string]string, slash, emptyMatch bool) *route {
+	// If path is empty, return route at this node, or the wildcard with
+	// an empty capture
 	if path == "" {
+		if node.route == nil && node.wildcard != nil && (slash || emptyMatch) {
+			params[node.wildcard.paramName] = ""
+			return node.wildcard.route
+		}
 		return node.route
 	}
 
@@ -237,8 +351,7 @@ func search(node *radixNode, path string, params map[string]string) *route {
 				remaining := path[len(child.path):]
 				if remaining == "" || remaining[0] == '/' {
 					// Matched - remove leading slash from remaining
-					remaining = strings.TrimPrefix(remaining, "/")
-					if route := search(child, remaining, params); route != nil {
+					if route := search(child, strings.TrimPrefix(remaining, "/"), params, remaining != "", emptyMatch); route != nil {
 						return route
 					}
 				}
@@ -254,7 +367,7 @@ func search(node *radixNode, path string, params map[string]string) *route {
 			if segment != "" {
 				// Save param value
 				params[child.paramName] = segment
-				if route := search(child, remaining, params); route != nil {
+				if route := search(child, remaining, params, len(segment) < len(path), emptyMatch); route != nil {
 					return route
 				}
 				// Backtrack - remove param
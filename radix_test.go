@@ -1,8 +1,11 @@
 package cosan
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -90,6 +93,120 @@ func TestWildcardParameters(t *testing.T) {
 	}
 }
 
+func TestWildcardEmptyMatch(t *testing.T) {
+	tests := []struct {
+		path       string
+		emptyMatch bool
+		expectCode int
+		expectBody string
+	}{
+		{"/static", false, 404, "404 page not found\n"},
+		{"/static/", false, 200, "[]"},
+		{"/static/a/b", false, 200, "[a/b]"},
+		{"/static", true, 200, "[]"},
+		{"/static/", true, 200, "[]"},
+		{"/static/a/b", true, 200, "[a/b]"},
+		{"/users/7", false, 404, "404 page not found\n"},
+		{"/users/7/", false, 200, "[]"},
+		{"/users/7", true, 200, "[]"},
+	}
+
+	for _, tt := range tests {
+		router := New(WithWildcardEmptyMatch(tt.emptyMatch))
+		handler := func(ctx Context) error {
+			for _, name := range []string{"path", "rest"} {
+				if v, ok := ctx.(*context).params[name]; ok {
+					return ctx.String(200, "[%s]", v)
+				}
+			}
+			return ctx.String(500, "no capture")
+		}
+		router.GET("/static/*path", handler)
+		router.GET("/users/:id/*rest", handler)
+
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.expectCode || w.Body.String() != tt.expectBody {
+			t.Errorf("%s (empty match %v): expected %d %q, got %d %q",
+				tt.path, tt.emptyMatch, tt.expectCode, tt.expectBody, w.Code, w.Body.String())
+		}
+	}
+
+	// A root wildcard matches "/" itself
+	router := New()
+	router.GET("/*all", func(ctx Context) error {
+		return ctx.String(200, "[%s]", ctx.Param("all"))
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != 200 || w.Body.String() != "[]" {
+		t.Errorf("/: expected 200 \"[]\", got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestRadixMatcher_DegeneratePaths(t *testing.T) {
+	m := newRadixMatcher()
+	for _, pattern := range []string{"/users", "/users/:id", "/files/*filepath", "/ünïcödé/:name"} {
+		if err := m.Register("GET", pattern, func(ctx Context) error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	long := strings.Repeat("x", 1<<16)
+	deep := strings.Repeat("a/", 1<<12)
+	tests := []struct {
+		path    string
+		pattern string // "" for no match
+		param   string
+		value   string
+	}{
+		{"", "", "", ""},
+		{"/", "", "", ""},
+		{"//", "", "", ""},
+		{"///users", "", "", ""},
+		{"/users/", "/users", "", ""},
+		{"/users//", "", "", ""},
+		{"/users//posts", "", "", ""},
+		{"/../", "", "", ""},
+		{"/users/../admin", "", "", ""},
+		{"/users/..", "/users/:id", "id", ".."},
+		{"/files/../etc/passwd", "/files/*filepath", "filepath", "../etc/passwd"},
+		{"/files//", "/files/*filepath", "filepath", "/"},
+		{"/ünïcödé/名前", "/ünïcödé/:name", "name", "名前"},
+		{"/ünïcöd", "", "", ""},
+		{"/users/\xff\xfe", "/users/:id", "id", "\xff\xfe"},
+		{"/users/" + long, "/users/:id", "id", long},
+		{"/files/" + deep, "/files/*filepath", "filepath", deep},
+		{"/" + deep, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		rt, params, found := m.Match("GET", tt.path)
+		name := tt.path
+		if len(name) > 40 {
+			name = name[:40] + "..."
+		}
+		if tt.pattern == "" {
+			if found {
+				t.Errorf("%q: expected no match, got %s", name, rt.Pattern())
+			}
+			continue
+		}
+		if !found || rt.Pattern() != tt.pattern {
+			t.Errorf("%q: expected %s, got found=%v", name, tt.pattern, found)
+			continue
+		}
+		if tt.param != "" && params[tt.param] != tt.value {
+			t.Errorf("%q: expected %s=%q, got %q", name, tt.param, tt.value, params[tt.param])
+		}
+	}
+}
+
 // TestStaticVsParamPriority tests that static routes have priority over params.
 func TestStaticVsParamPriority(t *testing.T) {
 	router := New()
@@ -136,6 +253,136 @@ func TestStaticVsParamPriority(t *testing.T) {
 	}
 }
 
+// TestStaticVsParamPriority_ThreeLevels tests static-over-param priority
+// with backtracking across several levels.
+func TestStaticVsParamPriority_ThreeLevels(t *testing.T) {
+	router := New()
+	for _, pattern := range []string{
+		"/users/:id/edit",
+		"/users/me/edit",
+		"/users/me",
+		"/users/:id",
+		"/users/:id/posts/:post",
+		"/users/me/posts/latest",
+		"/res1",
+		"/res10",
+	} {
+		pattern := pattern
+		router.GET(pattern, func(ctx Context) error {
+			return ctx.String(200, "%s id=%s post=%s", pattern, ctx.Param("id"), ctx.Param("post"))
+		})
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/users/me", "/users/me id= post="},
+		{"/users/42", "/users/:id id=42 post="},
+		{"/users/me/edit", "/users/me/edit id= post="},
+		{"/users/42/edit", "/users/:id/edit id=42 post="},
+		{"/users/me/posts/latest", "/users/me/posts/latest id= post="},
+		// The static "me" branch dead-ends, so the match falls back to :id.
+		{"/users/me/posts/7", "/users/:id/posts/:post id=me post=7"},
+		{"/users/42/posts/latest", "/users/:id/posts/:post id=42 post=latest"},
+		// A static segment extending a sibling's text is its own route.
+		{"/res1", "/res1 id= post="},
+		{"/res10", "/res10 id= post="},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != 200 || w.Body.String() != tt.want {
+			t.Errorf("%s: expected %q, got %d %q", tt.path, tt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestStaticBranchBacktracking(t *testing.T) {
+	router := New()
+	router.GET("/a/b/c", func(ctx Context) error {
+		return ctx.String(200, "static")
+	})
+	router.GET("/a/:x/d", func(ctx Context) error {
+		return ctx.String(200, "param x=%s", ctx.Param("x"))
+	})
+	router.GET("/a/b/:y/e", func(ctx Context) error {
+		return ctx.String(200, "deep y=%s", ctx.Param("y"))
+	})
+	router.GET("/a/:x/f/g", func(ctx Context) error {
+		return ctx.String(200, "param x=%s y=%s", ctx.Param("x"), ctx.Param("y"))
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/a/b/c", "static"},
+		{"/a/b/d", "param x=b"},
+		{"/a/z/d", "param x=z"},
+		{"/a/b/q/e", "deep y=q"},
+		// The static branch binds :y before dead-ending; it must not leak.
+		{"/a/b/f/g", "param x=b y="},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != 200 || w.Body.String() != tt.want {
+			t.Errorf("%s: expected %q, got %d %q", tt.path, tt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestParamPercentDecoding(t *testing.T) {
+	router := New()
+	router.GET("/users/:name", func(ctx Context) error {
+		return ctx.String(200, "user %s", ctx.Param("name"))
+	})
+	router.GET("/users/:name/files/*path", func(ctx Context) error {
+		return ctx.String(200, "user %s path %s", ctx.Param("name"), ctx.Param("path"))
+	})
+	router.GET("/files/a/b", func(ctx Context) error {
+		return ctx.String(200, "nested")
+	})
+	router.GET("/café/:name", func(ctx Context) error {
+		return ctx.String(200, "café %s", ctx.Param("name"))
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/users/John%20Doe", "user John Doe"},
+		{"/users/J%C3%BCrgen", "user Jürgen"},
+		{"/users/Jürgen", "user Jürgen"},
+		{"/users/a%2Fb", "user a/b"},
+		{"/users/a%2Fb/files/x%2Fy/z", "user a/b path x/y/z"},
+		{"/users/a%252F", "user a%2F"},
+		{"/files/a/b", "nested"},
+		{"/caf%C3%A9/menu", "café menu"},
+		{"/caf%c3%a9/menu", "café menu"},
+		{"/caf%C3%A9/a%2Fb", "café a/b"},
+		{"/caf%C3%A9/a%2Fb%20c", "café a/b c"},
+		{"/%66iles/a/b", "nested"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != 200 || w.Body.String() != tt.want {
+			t.Errorf("%s: expected %q, got %d %q", tt.path, tt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
 // TestNestedParameters tests parameters in nested route groups.
 func TestNestedParameters(t *testing.T) {
 	router := New()
@@ -361,3 +608,107 @@ func BenchmarkRadixComplexRoute(b *testing.B) {
 		router.ServeHTTP(w, req)
 	}
 }
+
+// TestRadixMatcher_Routes tests route enumeration from the tree.
+func TestRadixMatcher_Routes(t *testing.T) {
+	m := newRadixMatcher()
+	handler := func(ctx Context) error { return nil }
+
+	patterns := []string{"/users/:id", "/users", "/files/*path", "/users/:id/posts"}
+	for _, p := range patterns {
+		if err := m.Register("GET", p, handler); err != nil {
+			t.Fatalf("Register %s failed: %v", p, err)
+		}
+	}
+	m.Register("DELETE", "/users/:id", handler)
+	m.Compile()
+
+	routes := m.Routes()
+	if len(routes) != 5 {
+		t.Fatalf("Expected 5 routes, got %d", len(routes))
+	}
+	for i, p := range patterns {
+		if routes[i].Pattern() != p {
+			t.Errorf("Route %d: expected %s, got %s", i, p, routes[i].Pattern())
+		}
+	}
+	if routes[4].Method() != "DELETE" {
+		t.Errorf("Expected DELETE route last, got %s", routes[4].Method())
+	}
+}
+
+func TestRadixMatcher_ParamNameConflict(t *testing.T) {
+	m := newRadixMatcher()
+	handler := func(ctx Context) error { return nil }
+
+	if err := m.Register("GET", "/users/:id", handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := m.Register("GET", "/users/:id/posts", handler); err != nil {
+		t.Errorf("Same param name should share the node, got %v", err)
+	}
+	if err := m.Register("POST", "/users/:userId", handler); err != nil {
+		t.Errorf("Different methods should not conflict, got %v", err)
+	}
+
+	err := m.Register("GET", "/users/:userId", handler)
+	if !errors.Is(err, ErrConflictingRoutes) {
+		t.Fatalf("Expected ErrConflictingRoutes, got %v", err)
+	}
+	if !strings.Contains(err.Error(), ":userId") || !strings.Contains(err.Error(), ":id") {
+		t.Errorf("Expected both parameter names in error, got %q", err)
+	}
+}
+
+func TestOptionalParameter(t *testing.T) {
+	router := New()
+	router.GET("/posts/:id/:slug?", func(ctx Context) error {
+		slug, present := ctx.Params()["slug"]
+		return ctx.String(200, ctx.Param("id")+"|"+slug+"|"+strconv.FormatBool(present))
+	})
+
+	tests := []struct {
+		path string
+		want string
+		code int
+	}{
+		{"/posts/10", "10||false", 200},
+		{"/posts/10/my-title", "10|my-title|true", 200},
+		{"/posts/10/my-title/extra", "", 404},
+		{"/posts", "", 404},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("Path %s: expected status %d, got %d", tt.path, tt.code, w.Code)
+		}
+		if tt.code == 200 && w.Body.String() != tt.want {
+			t.Errorf("Path %s: expected %q, got %q", tt.path, tt.want, w.Body.String())
+		}
+	}
+
+	m := newRadixMatcher()
+	m.Register("GET", "/posts/:id/:slug?", func(ctx Context) error { return nil })
+	if routes := m.Routes(); len(routes) != 1 || routes[0].Pattern() != "/posts/:id/:slug?" {
+		t.Errorf("Expected the optional route to be listed once, got %d routes", len(routes))
+	}
+}
+
+func TestOptionalParameter_Conflict(t *testing.T) {
+	m := newRadixMatcher()
+	handler := func(ctx Context) error { return nil }
+
+	if err := m.Register("GET", "/posts/:id", handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := m.Register("GET", "/posts/:id/:slug?", handler); !errors.Is(err, ErrConflictingRoutes) {
+		t.Errorf("Expected ErrConflictingRoutes, got %v", err)
+	}
+	if err := m.Register("GET", "/a/:x?/b", handler); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("Expected ErrInvalidPattern for a non-final optional parameter, got %v", err)
+	}
+}
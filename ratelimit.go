@@ -0,0 +1,129 @@
+package cosan
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the route to rps requests per second per client IP,
+// allowing bursts of up to burst requests. Requests over the limit fail with
+// an *HTTPError with status 429 and a Retry-After header.
+//
+// The limiter runs inside global and group middleware (so logging and
+// recovery still see rejected requests) and before the route's timeout and
+// handler. The client IP is taken from the connection's remote address;
+// behind a proxy, restore the client address before the router runs.
+//
+// WithRateLimit panics if rps is not positive.
+//
+// Example:
+//
+//	router.POST("/export", ExportHandler, cosan.WithRateLimit(1, 5))
+func WithRateLimit(rps float64, burst int) RouteOption {
+	if rps <= 0 {
+		panic(fmt.Sprintf("cosan: WithRateLimit rps must be positive, got %v", rps))
+	}
+	return func(r *route) {
+		r.rateLimit = newRateLimiter(rps, burst)
+	}
+}
+
+// rateLimiterSweepInterval is how often idle client buckets are dropped.
+const rateLimiterSweepInterval = time.Minute
+
+// rateLimiter is a token-bucket limiter with one bucket per client key.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // tokens per second
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// tokenBucket tracks the tokens available to one client.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:    rps,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token for key. When none is available it reports how long
+// until the next token.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets that have refilled completely; a new bucket for the
+// same client starts full, so forgetting them changes nothing.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitHandler rejects requests from clients that exceed the limiter.
+func rateLimitHandler(next HandlerFunc, l *rateLimiter) HandlerFunc {
+	return func(ctx Context) error {
+		ok, retryAfter := l.allow(clientIP(ctx.Request()))
+		if !ok {
+			seconds := int((retryAfter + time.Second - 1) / time.Second)
+			ctx.Header().Set("Retry-After", strconv.Itoa(seconds))
+			return NewHTTPError(http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests))
+		}
+		return next(ctx)
+	}
+}
+
+// clientIP returns the host part of the request's remote address.
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
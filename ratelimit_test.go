@@ -0,0 +1,83 @@
+package cosan
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	r := New()
+	r.POST("/export", func(ctx Context) error {
+		return ctx.String(200, "ok")
+	}, WithRateLimit(1, 2))
+	r.GET("/free", func(ctx Context) error {
+		return ctx.String(200, "ok")
+	})
+
+	do := func(method, path, remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remote
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := do("POST", "/export", "10.0.0.1:1234"); w.Code != 200 {
+			t.Fatalf("Request %d within burst: expected 200, got %d", i+1, w.Code)
+		}
+	}
+
+	w := do("POST", "/export", "10.0.0.1:5678")
+	if w.Code != 429 {
+		t.Errorf("Expected 429 once the burst is spent, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected Retry-After: 1, got %q", w.Header().Get("Retry-After"))
+	}
+
+	if w := do("POST", "/export", "10.0.0.2:1234"); w.Code != 200 {
+		t.Errorf("Expected other clients to be unaffected, got %d", w.Code)
+	}
+	for i := 0; i < 5; i++ {
+		if w := do("GET", "/free", "10.0.0.1:1234"); w.Code != 200 {
+			t.Fatalf("Expected routes without a limit to be unaffected, got %d", w.Code)
+		}
+	}
+}
+
+func TestRateLimiter_Refill(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(2, 1)
+	l.now = func() time.Time { return now }
+
+	if ok, _ := l.allow("a"); !ok {
+		t.Fatal("Expected first request to be allowed")
+	}
+	ok, retry := l.allow("a")
+	if ok || retry != 500*time.Millisecond {
+		t.Errorf("Expected rejection with 500ms retry, got %v %v", ok, retry)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.allow("a"); !ok {
+		t.Error("Expected a token after refilling")
+	}
+
+	now = now.Add(2 * rateLimiterSweepInterval)
+	l.allow("b")
+	if _, ok := l.buckets["a"]; ok {
+		t.Error("Expected idle bucket to be swept")
+	}
+}
+
+func TestWithRateLimit_InvalidRate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected WithRateLimit to panic for a rate of 0")
+		}
+	}()
+
+	WithRateLimit(0, 5)
+}
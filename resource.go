@@ -0,0 +1,91 @@
+package cosan
+
+// ResourceController is a controller registered with Router.Resource. It
+// may implement any of ResourceIndexer, ResourceCreator, ResourceShower,
+// ResourceUpdater, and ResourceDestroyer; only the implemented actions
+// are routed.
+type ResourceController interface{}
+
+// ResourceIndexer handles GET /name.
+type ResourceIndexer interface {
+	Index(ctx Context) error
+}
+
+// ResourceCreator handles POST /name.
+type ResourceCreator interface {
+	Create(ctx Context) error
+}
+
+// ResourceShower handles GET /name/:id.
+type ResourceShower interface {
+	Show(ctx Context) error
+}
+
+// ResourceUpdater handles PUT /name/:id.
+type ResourceUpdater interface {
+	Update(ctx Context) error
+}
+
+// ResourceDestroyer handles DELETE /name/:id.
+type ResourceDestroyer interface {
+	Destroy(ctx Context) error
+}
+
+// ResourceParam is the path parameter naming the item in Resource routes.
+const ResourceParam = "id"
+
+// Resource registers the conventional REST routes for the actions
+// controller implements:
+//
+//	GET    /name      Index
+//	POST   /name      Create
+//	GET    /name/:id  Show
+//	PUT    /name/:id  Update
+//	DELETE /name/:id  Destroy
+//
+// It panics if controller implements none of them.
+//
+// Example:
+//
+//	router.Resource("/users", userController)
+func (r *router) Resource(name string, controller ResourceController) {
+	registerResource(r.Route, name, controller)
+}
+
+// Resource registers the conventional REST routes for controller under
+// name within the group.
+func (g *routerGroup) Resource(name string, controller ResourceController) {
+	registerResource(g.Route, name, controller)
+}
+
+// registerResource wires controller's actions through route builders.
+func registerResource(route func(pattern string) RouteBuilder, name string, controller ResourceController) {
+	collection := joinPath(normalizePrefix(name), "")
+	item := joinPath(normalizePrefix(name), ":"+ResourceParam)
+
+	registered := false
+	if c, ok := controller.(ResourceIndexer); ok {
+		route(collection).GET(c.Index)
+		registered = true
+	}
+	if c, ok := controller.(ResourceCreator); ok {
+		route(collection).POST(c.Create)
+		registered = true
+	}
+	if c, ok := controller.(ResourceShower); ok {
+		route(item).GET(c.Show)
+		registered = true
+	}
+	if c, ok := controller.(ResourceUpdater); ok {
+		route(item).PUT(c.Update)
+		registered = true
+	}
+	if c, ok := controller.(ResourceDestroyer); ok {
+		route(item).DELETE(c.Destroy)
+		registered = true
+	}
+
+	if !registered {
+		panic("cosan: resource " + collection + " has no Index, Create, Show, Update, or Destroy method")
+	}
+}
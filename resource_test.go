@@ -0,0 +1,61 @@
+package cosan
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+type testResource struct{}
+
+func (testResource) Index(ctx Context) error  { return ctx.String(200, "index") }
+func (testResource) Create(ctx Context) error { return ctx.String(201, "create") }
+func (testResource) Show(ctx Context) error   { return ctx.String(200, "show %s", ctx.Param("id")) }
+func (testResource) Update(ctx Context) error { return ctx.String(200, "update %s", ctx.Param("id")) }
+func (testResource) Destroy(ctx Context) error {
+	return ctx.String(200, "destroy %s", ctx.Param("id"))
+}
+
+type readOnlyResource struct{}
+
+func (readOnlyResource) Show(ctx Context) error { return ctx.String(200, "show %s", ctx.Param("id")) }
+
+func TestResource(t *testing.T) {
+	r := New()
+	r.Resource("/users", testResource{})
+	r.Group("/api").Resource("reports", readOnlyResource{})
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{"GET", "/users", 200, "index"},
+		{"POST", "/users", 201, "create"},
+		{"GET", "/users/7", 200, "show 7"},
+		{"PUT", "/users/7", 200, "update 7"},
+		{"DELETE", "/users/7", 200, "destroy 7"},
+		{"GET", "/api/reports/3", 200, "show 3"},
+		{"GET", "/api/reports", 404, "404 page not found\n"},
+		{"DELETE", "/api/reports/3", 405, "Method Not Allowed\n"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.code, tt.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestResource_NoActions(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for a controller without actions")
+		}
+	}()
+
+	New().Resource("/empty", struct{}{})
+}
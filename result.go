@@ -0,0 +1,74 @@
+package cosan
+
+// Result is a response a handler returns instead of writing it, separating
+// what to respond with from how it is written. Handlers returning a Result
+// are adapted with Handle.
+type Result interface {
+	// Render writes the response.
+	Render(ctx Context) error
+}
+
+// ResultHandler is a handler that returns its response as a Result.
+type ResultHandler func(ctx Context) (Result, error)
+
+// Handle adapts a ResultHandler to a HandlerFunc: a returned error goes to
+// the error handler as usual, otherwise the Result is rendered. A nil
+// Result renders nothing, for handlers that wrote the response themselves.
+//
+// Example:
+//
+//	router.GET("/users/:id", cosan.Handle(func(ctx cosan.Context) (cosan.Result, error) {
+//		user, err := store.Find(ctx.Param("id"))
+//		if err != nil {
+//			return nil, err
+//		}
+//		return cosan.JSON(200, user), nil
+//	}))
+func Handle(h ResultHandler) HandlerFunc {
+	return func(ctx Context) error {
+		result, err := h(ctx)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return nil
+		}
+		return result.Render(ctx)
+	}
+}
+
+// JSONResult renders Value as JSON with status Code. Tests can inspect the
+// Result returned by JSON by asserting it to *JSONResult.
+type JSONResult struct {
+	Code  int
+	Value interface{}
+}
+
+// JSON returns a Result that writes v as JSON with the given status, as
+// Context.JSON does.
+func JSON(code int, v interface{}) Result {
+	return &JSONResult{Code: code, Value: v}
+}
+
+// Render implements Result.
+func (r *JSONResult) Render(ctx Context) error {
+	return ctx.JSON(r.Code, r.Value)
+}
+
+// HTMLResult renders HTML with status Code. Tests can inspect the Result
+// returned by HTML by asserting it to *HTMLResult.
+type HTMLResult struct {
+	Code int
+	HTML string
+}
+
+// HTML returns a Result that writes html with the given status, as
+// Context.HTML does.
+func HTML(code int, html string) Result {
+	return &HTMLResult{Code: code, HTML: html}
+}
+
+// Render implements Result.
+func (r *HTMLResult) Render(ctx Context) error {
+	return ctx.HTML(r.Code, r.HTML)
+}
@@ -0,0 +1,63 @@
+package cosan
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandle(t *testing.T) {
+	getUser := func(ctx Context) (Result, error) {
+		switch ctx.Param("id") {
+		case "0":
+			return nil, NewHTTPError(404, "user not found")
+		case "raw":
+			return nil, ctx.String(200, "written directly")
+		}
+		return JSON(200, map[string]string{"id": ctx.Param("id")}), nil
+	}
+
+	r := New()
+	r.GET("/users/:id", Handle(getUser))
+	r.GET("/page", Handle(func(ctx Context) (Result, error) {
+		return HTML(201, "<h1>Hi</h1>"), nil
+	}))
+
+	tests := []struct {
+		path  string
+		code  int
+		ctype string
+		body  string
+	}{
+		{"/users/7", 200, "application/json", "{\"id\":\"7\"}\n"},
+		{"/users/0", 404, "text/plain; charset=utf-8", "user not found"},
+		{"/users/raw", 200, "text/plain; charset=utf-8", "written directly"},
+		{"/page", 201, "text/html; charset=utf-8", "<h1>Hi</h1>"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != tt.code || w.Header().Get("Content-Type") != tt.ctype || w.Body.String() != tt.body {
+			t.Errorf("%s: expected %d %q %q, got %d %q %q", tt.path, tt.code, tt.ctype, tt.body,
+				w.Code, w.Header().Get("Content-Type"), w.Body.String())
+		}
+	}
+}
+
+func TestResult_Inspectable(t *testing.T) {
+	// Handlers returning Results can be tested without a response recorder
+	handler := func(ctx Context) (Result, error) {
+		return JSON(201, "created"), nil
+	}
+
+	result, err := handler(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jr, ok := result.(*JSONResult)
+	if !ok || jr.Code != 201 || jr.Value != "created" {
+		t.Errorf("Expected JSONResult 201 created, got %#v", result)
+	}
+}
@@ -0,0 +1,78 @@
+package cosan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RouteDef describes a route for RegisterRoutes.
+type RouteDef struct {
+	// Method is the HTTP method, e.g. http.MethodGet.
+	Method string
+
+	// Pattern is the route pattern, e.g. "/users/:id".
+	Pattern string
+
+	// Handler handles matching requests.
+	Handler HandlerFunc
+
+	// Name sets the route name, as WithName does. Optional.
+	Name string
+
+	// Tags sets the route tags, as WithTags does. Optional.
+	Tags []string
+
+	// Middleware runs for this route only, inside the global middleware.
+	Middleware []Middleware
+}
+
+// RegisterRoutes registers every route in defs. Unlike the method
+// registrars it does not panic: routes that cannot be registered (missing
+// handler, invalid pattern, conflict) are skipped, the rest are registered,
+// and the returned error joins one error per failed entry.
+//
+// Example:
+//
+//	err := router.RegisterRoutes([]cosan.RouteDef{
+//		{Method: http.MethodGet, Pattern: "/users", Handler: ListUsers, Name: "users.list"},
+//		{Method: http.MethodPost, Pattern: "/users", Handler: CreateUser, Middleware: []cosan.Middleware{auth}},
+//	})
+func (r *router) RegisterRoutes(defs []RouteDef) error {
+	return registerRouteDefs(r.addRoute, defs)
+}
+
+// RegisterRoutes registers every route in defs under the group's prefix.
+func (g *routerGroup) RegisterRoutes(defs []RouteDef) error {
+	return registerRouteDefs(func(method, pattern string, handler HandlerFunc, opts ...RouteOption) error {
+		return g.router.addRoute(method, joinPath(g.prefix, pattern), handler, append([]RouteOption{inGroup(g)}, opts...)...)
+	}, defs)
+}
+
+// registerRouteDefs registers each definition through add, collecting the
+// errors of the entries that fail.
+func registerRouteDefs(add func(method, pattern string, handler HandlerFunc, opts ...RouteOption) error, defs []RouteDef) error {
+	var errs []error
+	for i, def := range defs {
+		if def.Method == "" || def.Handler == nil {
+			errs = append(errs, fmt.Errorf("cosan: route %d (%s %s) needs a method and a handler", i, def.Method, def.Pattern))
+			continue
+		}
+
+		var opts []RouteOption
+		if def.Name != "" {
+			opts = append(opts, WithName(def.Name))
+		}
+		if len(def.Tags) > 0 {
+			opts = append(opts, WithTags(def.Tags...))
+		}
+		if len(def.Middleware) > 0 {
+			opts = append(opts, withMiddleware(def.Middleware))
+		}
+
+		if err := add(def.Method, def.Pattern, def.Handler, opts...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package cosan
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	r := New()
+	handler := func(ctx Context) error { return ctx.String(200, "%s", ctx.RouteName()) }
+	tagged := MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.Header().Set("X-Route-Middleware", "1")
+			return next(ctx)
+		}
+	})
+
+	err := r.Group("/api").RegisterRoutes([]RouteDef{
+		{Method: "GET", Pattern: "/users", Handler: handler, Name: "users.list", Tags: []string{"users"}},
+		{Method: "POST", Pattern: "/users", Handler: handler, Name: "users.create", Middleware: []Middleware{tagged}},
+		{Method: "GET", Pattern: "/users/:", Handler: handler},
+		{Method: "GET", Pattern: "/users", Handler: handler},
+		{Method: "DELETE", Pattern: "/users/:id"},
+		{Method: "GET", Pattern: "/users/:id", Handler: handler, Name: "users.show"},
+	})
+
+	if err == nil {
+		t.Fatal("Expected an error for the bad entries")
+	}
+	if !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("Expected the error to wrap ErrInvalidPattern, got %v", err)
+	}
+	for _, want := range []string{"GET /api/users/:", "duplicate route registration: GET /api/users", "route 4 (DELETE /users/:id)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got %q", want, err)
+		}
+	}
+	if n := len(strings.Split(err.Error(), "\n")); n != 3 {
+		t.Errorf("Expected 3 joined errors, got %d: %q", n, err)
+	}
+
+	tests := []struct {
+		method     string
+		path       string
+		body       string
+		middleware string
+	}{
+		{"GET", "/api/users", "users.list", ""},
+		{"POST", "/api/users", "users.create", "1"},
+		{"GET", "/api/users/7", "users.show", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != 200 || w.Body.String() != tt.body || w.Header().Get("X-Route-Middleware") != tt.middleware {
+			t.Errorf("%s %s: expected 200 %q (middleware %q), got %d %q (middleware %q)", tt.method, tt.path,
+				tt.body, tt.middleware, w.Code, w.Body.String(), w.Header().Get("X-Route-Middleware"))
+		}
+	}
+
+	if info := r.FindRoute("users.list"); info == nil || len(info.Tags) != 1 || info.Tags[0] != "users" {
+		t.Errorf("Expected tagged users.list route, got %+v", info)
+	}
+}
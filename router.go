@@ -1,20 +1,36 @@
 package cosan
 
 import (
+	"bufio"
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"runtime/debug"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // statusRecorder wraps http.ResponseWriter to capture status code
+// and count the body bytes written.
 type statusRecorder struct {
 	http.ResponseWriter
 	statusCode int
 	written    bool
+	bytes      int64
+	hijacked   bool
 }
 
 func (r *statusRecorder) WriteHeader(code int) {
-	if !r.written {
+	if !r.written && !r.hijacked {
 		r.statusCode = code
 		r.written = true
 		r.ResponseWriter.WriteHeader(code)
@@ -22,10 +38,41 @@ func (r *statusRecorder) WriteHeader(code int) {
 }
 
 func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.hijacked {
+		return 0, http.ErrHijacked
+	}
 	if !r.written {
 		r.WriteHeader(200)
 	}
-	return r.ResponseWriter.Write(b)
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher when the underlying writer supports it.
+// Flushing commits the response with the status written so far.
+func (r *statusRecorder) Flush() {
+	if !r.written {
+		r.WriteHeader(r.statusCode)
+	}
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker by delegating to the underlying writer.
+// A hijacked response reports 101 Switching Protocols to after-response
+// hooks, since no status is ever written through the recorder.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil {
+		r.hijacked = true
+		r.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
 }
 
 // router is the default implementation of the Router interface.
@@ -37,14 +84,66 @@ type router struct {
 	compiled   bool
 	hooks      *hooks
 	mu         sync.RWMutex
+
+	deprecationHeaders   bool
+	recovery             bool
+	dynamic              bool
+	strictJSON           bool
+	validator            Validator
+	logger               *slog.Logger
+	jsonCodec            JSONMarshaler
+	protoCodec           ProtoMarshaler
+	msgPackCodec         MsgPackMarshaler
+	notFound             HandlerFunc
+	fallback             HandlerFunc
+	prefixNotFound       []prefixNotFound // group not-found handlers, longest prefix first
+	unmatched            atomic.Pointer[unmatchedChains]
+	maxPathLength        int
+	charset              string
+	maxMultipartMemory   int64
+	requestTimeout       time.Duration
+	methods              []string // distinct methods with routes, for 405 detection
+	contextPool          *sync.Pool
+	wildcardEmptyMatch   bool
+	contextFactory       ContextFactory
+	precompressed        bool // serve .br/.gz variants from Static and File
+	maxBindBytes         int64
+	readiness            func() bool
+	readinessGate        bool        // 503 for every route until readiness passes
+	ready                atomic.Bool // readiness has passed
+	handlerWrapper       HandlerWrapper
+	cookieDefaults       *http.Cookie // nil for the built-in defaults
+	requireResponse      bool
+	unsupportedMediaType HandlerFunc // 415 for Consumes routes, in the global middleware; built at compile time
+	serversMu            sync.Mutex
+	servers              map[*http.Server]struct{} // started by Listen, ListenTLS, and Serve, for Shutdown
 }
 
 // route represents a registered HTTP route.
 type route struct {
-	method   string
-	pattern  string
-	handler  HandlerFunc
-	metadata *RouteMetadata
+	method     string
+	pattern    string
+	handler    HandlerFunc
+	metadata   *RouteMetadata
+	chain      HandlerFunc  // handler wrapped with middleware, built at compile time
+	seq        int          // registration order, assigned by the matcher
+	group      *routerGroup // group the route was registered through; nil for the router
+	timeout    time.Duration
+	timeoutSet bool // WithTimeout was given, overriding the router default
+	rateLimit  *rateLimiter
+	middleware []Middleware // route-scoped middleware, inside the global middleware
+	source     string       // file:line of the registering call, for conflict messages
+
+	maxBindBytes    int64
+	maxBindBytesSet bool // WithBindLimit was given, overriding the router default
+	ungated         bool // served before readiness passes (health endpoints)
+
+	consumes  []string // request media types served; empty for any
+	produces  []string // response media types; requests accepting none get 406
+	variants  []*route // with this route, those sharing its method and pattern, chosen by Content-Type
+	variantOf *route   // route the matcher holds, for variants
+
+	cacheControl []string // Cache-Control directives set on responses
 }
 
 // Pattern returns the route pattern.
@@ -52,6 +151,14 @@ func (r *route) Pattern() string {
 	return r.pattern
 }
 
+// name returns the name set with WithName, or "".
+func (r *route) name() string {
+	if r.metadata == nil {
+		return ""
+	}
+	return r.metadata.Name
+}
+
 // Method returns the HTTP method.
 func (r *route) Method() string {
 	return r.method
@@ -62,6 +169,32 @@ func (r *route) Handler() HandlerFunc {
 	return r.handler
 }
 
+// serve records the route on the context and runs its compiled handler chain.
+// It is the handler registered with the matcher. Routes with Consumes
+// constraints first select the route for the request's Content-Type.
+func (r *route) serve(ctx Context) error {
+	c, ok := unwrapContext(ctx)
+	rt := r
+	if r.consumes != nil || r.variants != nil {
+		if rt = r.consumer(ctx.Request().Header.Get("Content-Type")); rt == nil {
+			// No route's chain runs, so the 415 gets its own chain in the
+			// global middleware
+			if ok {
+				c.route = unsupportedMediaTypeRoute
+				if c.router != nil && c.router.unsupportedMediaType != nil {
+					return c.router.unsupportedMediaType(ctx)
+				}
+			}
+			return unsupportedMediaTypeHandler(ctx)
+		}
+	}
+
+	if ok {
+		c.route = rt
+	}
+	return rt.chain(ctx)
+}
+
 // New creates a new Router instance with default configuration.
 //
 // Example:
@@ -76,6 +209,13 @@ func New(opts ...Option) Router {
 		middleware: make([]Middleware, 0),
 		matcher:    newRadixMatcher(), // Radix tree matcher with path parameters
 		compiled:   false,
+		recovery:   true,
+
+		maxPathLength:      DefaultMaxPathLength,
+		charset:            DefaultCharset,
+		maxMultipartMemory: DefaultMaxMultipartMemory,
+		maxBindBytes:       DefaultMaxBindBytes,
+		contextPool:        contextPool,
 	}
 
 	// Apply options
@@ -83,6 +223,18 @@ func New(opts ...Option) Router {
 		opt(r)
 	}
 
+	if r.dynamic {
+		if dm, ok := r.matcher.(dynamicMatcher); ok {
+			dm.setDynamic(true)
+		}
+	}
+
+	if r.wildcardEmptyMatch {
+		if wm, ok := r.matcher.(wildcardEmptyMatcher); ok {
+			wm.setWildcardEmptyMatch(true)
+		}
+	}
+
 	return r
 }
 
@@ -96,39 +248,125 @@ func WithMatcher(m Matcher) Option {
 	}
 }
 
+// WithRecovery enables or disables built-in panic recovery (enabled by default).
+// When enabled, a panicking handler is logged with its stack trace and the
+// panic is passed to the error handler as a *PanicError, producing a 500.
+// Disable it to let panics propagate, e.g. in tests.
+func WithRecovery(enabled bool) Option {
+	return func(r *router) {
+		r.recovery = enabled
+	}
+}
+
+// WithDynamicRoutes allows registering routes after the router has compiled
+// (i.e. after the first request), for plugin systems, runtime route reloads,
+// and feature-flagged endpoints.
+//
+// Performance tradeoff: by default the compiled route tree is immutable and
+// matched without locking. With dynamic routes enabled, every match takes a
+// read lock so that registrations can insert into the tree under a write lock.
+// Middleware must still be added before the first request.
+func WithDynamicRoutes(enabled bool) Option {
+	return func(r *router) {
+		r.dynamic = enabled
+	}
+}
+
+// DefaultMaxPathLength is the default limit set by WithMaxPathLength.
+const DefaultMaxPathLength = 8192
+
+// WithWildcardEmptyMatch controls whether a wildcard route such as
+// /static/*path also matches its bare prefix, /static, with an empty
+// capture. A wildcard always matches /static/ (empty capture) and
+// /static/a/b (capture "a/b"); by default /static matches only a route
+// registered for it explicitly and otherwise responds 404.
+func WithWildcardEmptyMatch(enabled bool) Option {
+	return func(r *router) {
+		r.wildcardEmptyMatch = enabled
+	}
+}
+
+// WithRequireResponse treats a handler that returns nil without writing
+// a status or body as a bug: the router logs a warning and passes
+// ErrNoResponse to the error handler, which responds 500 by default,
+// instead of sending an empty 200. Meant for development; disabled by
+// default.
+func WithRequireResponse(enabled bool) Option {
+	return func(r *router) {
+		r.requireResponse = enabled
+	}
+}
+
+// WithMaxPathLength rejects requests whose path is longer than n bytes with
+// 414 URI Too Long, before any route matching. Defaults to
+// DefaultMaxPathLength; n <= 0 disables the limit.
+func WithMaxPathLength(n int) Option {
+	return func(r *router) {
+		r.maxPathLength = n
+	}
+}
+
+// DefaultCharset is the charset String and HTML responses declare by default.
+const DefaultCharset = "utf-8"
+
+// WithDefaultCharset sets the charset declared in the Content-Type of
+// String and HTML responses (default DefaultCharset). An empty charset
+// omits the parameter. JSON responses never carry a charset.
+func WithDefaultCharset(charset string) Option {
+	return func(r *router) {
+		r.charset = charset
+	}
+}
+
+// WithStrictJSON makes Bind reject JSON bodies containing fields that are
+// not present in the target struct, catching client typos that would
+// otherwise be silently dropped.
+func WithStrictJSON(enabled bool) Option {
+	return func(r *router) {
+		r.strictJSON = enabled
+	}
+}
+
+// WithValidator sets the Validator used by Context.Validate.
+func WithValidator(v Validator) Option {
+	return func(r *router) {
+		r.validator = v
+	}
+}
+
 // GET registers a handler for GET requests.
-func (r *router) GET(pattern string, handler HandlerFunc) {
-	r.registerRoute(http.MethodGet, pattern, handler)
+func (r *router) GET(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	r.registerRoute(http.MethodGet, pattern, handler, opts...)
 }
 
 // POST registers a handler for POST requests.
-func (r *router) POST(pattern string, handler HandlerFunc) {
-	r.registerRoute(http.MethodPost, pattern, handler)
+func (r *router) POST(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	r.registerRoute(http.MethodPost, pattern, handler, opts...)
 }
 
 // PUT registers a handler for PUT requests.
-func (r *router) PUT(pattern string, handler HandlerFunc) {
-	r.registerRoute(http.MethodPut, pattern, handler)
+func (r *router) PUT(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	r.registerRoute(http.MethodPut, pattern, handler, opts...)
 }
 
 // DELETE registers a handler for DELETE requests.
-func (r *router) DELETE(pattern string, handler HandlerFunc) {
-	r.registerRoute(http.MethodDelete, pattern, handler)
+func (r *router) DELETE(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	r.registerRoute(http.MethodDelete, pattern, handler, opts...)
 }
 
 // PATCH registers a handler for PATCH requests.
-func (r *router) PATCH(pattern string, handler HandlerFunc) {
-	r.registerRoute(http.MethodPatch, pattern, handler)
+func (r *router) PATCH(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	r.registerRoute(http.MethodPatch, pattern, handler, opts...)
 }
 
 // OPTIONS registers a handler for OPTIONS requests.
-func (r *router) OPTIONS(pattern string, handler HandlerFunc) {
-	r.registerRoute(http.MethodOptions, pattern, handler)
+func (r *router) OPTIONS(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	r.registerRoute(http.MethodOptions, pattern, handler, opts...)
 }
 
 // HEAD registers a handler for HEAD requests.
-func (r *router) HEAD(pattern string, handler HandlerFunc) {
-	r.registerRoute(http.MethodHead, pattern, handler)
+func (r *router) HEAD(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	r.registerRoute(http.MethodHead, pattern, handler, opts...)
 }
 
 // Use registers middleware to be applied to all routes.
@@ -148,6 +386,25 @@ func (r *router) Use(middleware ...Middleware) {
 	r.middleware = append(r.middleware, middleware...)
 }
 
+// UsePrepend registers middleware ahead of all middleware registered so
+// far, so it runs first (outermost). The given middleware keep their order.
+// Use it for recovery or tracing that must wrap everything, e.g. when
+// handed a router that is already partly configured.
+//
+// Example:
+//
+//	router.UsePrepend(middleware.Recovery())
+func (r *router) UsePrepend(middleware ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.compiled {
+		panic("cosan: cannot add middleware after router is compiled")
+	}
+
+	r.middleware = append(append([]Middleware{}, middleware...), r.middleware...)
+}
+
 // Group creates a new route group with the given prefix.
 // Groups support scoped middleware and nested grouping.
 //
@@ -160,7 +417,25 @@ func (r *router) Group(prefix string) Router {
 	// For Phase 1, we'll return a simple group wrapper
 	return &routerGroup{
 		router: r,
-		prefix: prefix,
+		prefix: normalizePrefix(prefix),
+	}
+}
+
+// Version creates a group for an API version: routes are prefixed with
+// /version and tagged WithVersion(version), so the URL and the metadata
+// reported by GetRoutes cannot drift apart. A route's own WithVersion
+// option takes precedence.
+//
+// Example:
+//
+//	v1 := router.Version("v1")
+//	v1.GET("/users", ListUsers) // GET /v1/users, version "v1"
+func (r *router) Version(version string) Router {
+	version = strings.Trim(version, "/")
+	return &routerGroup{
+		router:  r,
+		prefix:  normalizePrefix(version),
+		version: version,
 	}
 }
 
@@ -174,57 +449,153 @@ func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// Ensure router is compiled
 	r.ensureCompiled()
 
+	if r.maxPathLength > 0 && len(req.URL.Path) > r.maxPathLength {
+		http.Error(w, http.StatusText(http.StatusRequestURITooLong), http.StatusRequestURITooLong)
+		return
+	}
+
 	// Execute before-request hooks
 	if err := r.executeBeforeHooks(req); err != nil {
-		ctx := newContext(w, req, nil)
-		r.handleError(ctx, err)
+		r.rejectRequest(w, req, err)
 		return
 	}
 
-	// Match route
-	routeInterface, params, found := r.matcher.Match(req.Method, req.URL.Path)
+	// Match route. An encoded slash (%2F) stays inside its segment, and
+	// parameters are decoded below.
+	path, escaped := routingPath(req.URL)
+	matched, params, found := r.matcher.Match(req.Method, path)
+	var head *headWriter
+	if !found && req.Method == http.MethodHead {
+		// HEAD without its own route is served by the GET route
+		if matched, params, found = r.matcher.Match(http.MethodGet, path); found {
+			head = &headWriter{ResponseWriter: w}
+			w = head
+		}
+	}
 	if !found {
-		// No route found - return 404
-		http.NotFound(w, req)
+		r.handleNotFound(w, req, path)
 		return
 	}
 
 	// Create context (using pool for performance)
-	ctx := acquireContext(w, req)
-	defer releaseContext(ctx)
+	ctx := acquireContextFrom(r.contextPool, w, req)
+	ctx.router = r
 
 	// Set params
 	for k, v := range params {
+		if escaped {
+			if decoded, err := url.PathUnescape(v); err == nil {
+				v = decoded
+			}
+		}
 		ctx.params[k] = v
 	}
 
-	// Get handler from route interface
-	handler := (*routeInterface).Handler()
-
-	// Apply middleware chain
-	for i := len(r.middleware) - 1; i >= 0; i-- {
-		handler = r.middleware[i].Process(handler)
+	// Get the route's compiled chain
+	handler := matched.Handler()
+	if rt, ok := matched.(*route); ok && rt.chain != nil {
+		handler = rt.serve
 	}
 
 	// Execute handler and capture status
-	var statusCode int
 	statusCapture := &statusRecorder{ResponseWriter: w, statusCode: 200}
 	ctx.res = statusCapture
+	ctx.recorder = statusCapture
 
-	if err := handler(ctx); err != nil {
-		r.handleError(ctx, err)
-		statusCode = statusCapture.statusCode
-	} else {
-		statusCode = statusCapture.statusCode
+	hctx := r.handlerContext(ctx)
+	defer r.releaseContexts(ctx, hctx)
+
+	err := r.execute(handler, hctx)
+	if err == nil && r.requireResponse && !statusCapture.written && !statusCapture.hijacked {
+		ctx.Logger().Warn("cosan: handler returned without writing a response")
+		err = ErrNoResponse
+	}
+	if err != nil {
+		r.handleError(hctx, err)
+	}
+	if head != nil {
+		head.finish()
 	}
 
 	// Execute after-response hooks
-	r.executeAfterHooks(req, statusCode)
+	r.executeAfterHooks(req, statusCapture.statusCode)
+}
+
+// routingPath returns the path u is matched on. It is the decoded Path
+// unless the request encodes a slash or percent sign: those must stay
+// encoded so "%2F" does not split a segment, so the path is RawPath with
+// every other escape decoded, and escaped reports that parameters still
+// need unescaping. Decoding the other escapes lets static segments match
+// however the client encoded them, e.g. "/caf%c3%a9" for "/café".
+func routingPath(u *url.URL) (path string, escaped bool) {
+	raw := u.RawPath
+	if raw == "" || !strings.Contains(raw, "%") {
+		return u.Path, false
+	}
+
+	var b strings.Builder
+	b.Grow(len(raw))
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '%' && i+2 < len(raw) {
+			if c, ok := unhex(raw[i+1], raw[i+2]); ok {
+				if c != '/' && c != '%' {
+					b.WriteByte(c)
+					i += 2
+					continue
+				}
+				escaped = true
+			}
+		}
+		b.WriteByte(raw[i])
+	}
+	if !escaped {
+		return u.Path, false
+	}
+	return b.String(), true
+}
+
+// unhex decodes the hex digits of a percent escape.
+func unhex(hi, lo byte) (byte, bool) {
+	h, ok1 := fromHex(hi)
+	l, ok2 := fromHex(lo)
+	return h<<4 | l, ok1 && ok2
+}
+
+// fromHex returns the value of the hex digit c.
+func fromHex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// execute runs the handler, converting a panic into a *PanicError when
+// recovery is enabled. The stack trace is logged, never sent to the client.
+func (r *router) execute(handler HandlerFunc, ctx Context) (err error) {
+	if r.recovery {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				req := ctx.Request()
+				log.Printf("cosan: panic serving %s %s: %v\n%s", req.Method, req.URL.Path, rec, debug.Stack())
+				err = &PanicError{Value: rec}
+			}
+		}()
+	}
+
+	return handler(ctx)
 }
 
 // Listen starts the HTTP server on the specified address.
 // This is a convenience method that creates an http.Server with reasonable
-// timeout defaults and starts listening.
+// timeout defaults and starts listening. Shutdown stops it gracefully.
 //
 // For production use, consider creating your own http.Server with custom
 // timeouts and configuration.
@@ -233,6 +604,79 @@ func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 //
 //	router.Listen(":8080")
 func (r *router) Listen(addr string) error {
+	server := r.newServer(addr)
+	defer r.forgetServer(server)
+
+	return server.ListenAndServe()
+}
+
+// ListenTLS starts an HTTPS server on the specified address with the same
+// timeout defaults as Listen. HTTP/2 is negotiated automatically through
+// ALPN; for cleartext HTTP/2 (h2c) see the h2c subpackage.
+//
+// Example:
+//
+//	router.ListenTLS(":8443", "cert.pem", "key.pem")
+func (r *router) ListenTLS(addr, certFile, keyFile string) error {
+	server := r.newServer(addr)
+	defer r.forgetServer(server)
+
+	return server.ListenAndServeTLS(certFile, keyFile)
+}
+
+// Serve serves HTTP on a listener the caller created, with the same
+// timeout defaults as Listen: an ephemeral port from net.Listen("tcp",
+// ":0"), a Unix socket, or a socket passed in by systemd. The listener is
+// closed when Serve returns. After Shutdown, Serve returns
+// http.ErrServerClosed.
+//
+// Example:
+//
+//	l, err := net.Listen("tcp", ":0")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	log.Printf("listening on %s", l.Addr())
+//	router.Serve(l)
+func (r *router) Serve(l net.Listener) error {
+	server := r.newServer("")
+	defer r.forgetServer(server)
+
+	return server.Serve(l)
+}
+
+// Shutdown gracefully stops the servers started by Listen, ListenTLS, and
+// Serve: they stop accepting connections, and Shutdown waits for active
+// requests to finish until ctx is done. Their Listen or Serve call then
+// returns http.ErrServerClosed.
+//
+// Example:
+//
+//	go router.Listen(":8080")
+//	<-stop // e.g. signal.NotifyContext
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	router.Shutdown(ctx)
+func (r *router) Shutdown(ctx stdcontext.Context) error {
+	r.serversMu.Lock()
+	servers := make([]*http.Server, 0, len(r.servers))
+	for server := range r.servers {
+		servers = append(servers, server)
+	}
+	r.serversMu.Unlock()
+
+	var errs []error
+	for _, server := range servers {
+		if err := server.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newServer creates an http.Server for the router with the Listen timeout
+// defaults and tracks it for Shutdown.
+func (r *router) newServer(addr string) *http.Server {
 	server := &http.Server{
 		Addr:         addr,
 		Handler:      r,
@@ -240,37 +684,140 @@ func (r *router) Listen(addr string) error {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
-	return server.ListenAndServe()
+
+	r.serversMu.Lock()
+	defer r.serversMu.Unlock()
+
+	if r.servers == nil {
+		r.servers = make(map[*http.Server]struct{})
+	}
+	r.servers[server] = struct{}{}
+	return server
 }
 
-// registerRoute registers a new route with the router.
-func (r *router) registerRoute(method, pattern string, handler HandlerFunc) {
+// forgetServer stops tracking a server once it has stopped serving.
+func (r *router) forgetServer(server *http.Server) {
+	r.serversMu.Lock()
+	defer r.serversMu.Unlock()
+
+	delete(r.servers, server)
+}
+
+// registerRoute registers a new route with the router, panicking if it
+// cannot be registered.
+func (r *router) registerRoute(method, pattern string, handler HandlerFunc, opts ...RouteOption) {
+	if err := r.addRoute(method, pattern, handler, opts...); err != nil {
+		panic(err.Error())
+	}
+}
+
+// addRoute registers a new route with the router. On error the router is
+// left unchanged.
+func (r *router) addRoute(method, pattern string, handler HandlerFunc, opts ...RouteOption) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if r.compiled {
-		panic("cosan: cannot register routes after router is compiled")
+	if r.compiled && !r.dynamic {
+		return errors.New("cosan: cannot register routes after router is compiled")
 	}
 
-	// Check for conflicts
-	for _, existing := range r.routes {
-		if existing.method == method && existing.pattern == pattern {
-			panic("cosan: duplicate route registration: " + method + " " + pattern)
-		}
-	}
+	source := registrationSource()
 
-	// Create and store route
+	// Create route
 	rt := &route{
 		method:  method,
 		pattern: pattern,
 		handler: handler,
+		source:  source,
+	}
+	for _, opt := range opts {
+		opt(rt)
+	}
+
+	// Route names identify a single route for FindRoute
+	if name := rt.name(); name != "" {
+		for _, existing := range r.routes {
+			if existing.name() == name {
+				return fmt.Errorf("cosan: duplicate route name %q: %s %s at %s (first used by %s %s at %s)",
+					name, method, pattern, source, existing.method, existing.pattern, existing.source)
+			}
+		}
+	}
+
+	// Check for conflicts. Routes for the same method and pattern may
+	// coexist when their Consumes constraints do not overlap; later ones
+	// are served through the first.
+	for _, existing := range r.routes {
+		if existing.method != method || existing.pattern != pattern || existing.variantOf != nil {
+			continue
+		}
+		if conflict := existing.consumesConflict(rt); conflict != nil {
+			return errors.New("cosan: duplicate route registration: " + method + " " + pattern +
+				" at " + source + " (first registered at " + conflict.source + ")")
+		}
+		if r.compiled {
+			return errors.New("cosan: route " + method + " " + pattern + " at " + source +
+				" shares its pattern with another Consumes route and must be registered before the router is compiled")
+		}
+		if existing.variants == nil {
+			existing.variants = []*route{existing}
+		}
+		rt.variantOf = existing
+		existing.variants = append(existing.variants, rt)
+		r.routes = append(r.routes, rt)
+		return nil
+	}
+
+	// Routes added to an already compiled (dynamic) router are compiled
+	// immediately, before the matcher can serve them
+	if r.compiled {
+		r.compileRoute(rt)
+	}
+
+	// Register with matcher; the route's chain is built at compile time.
+	// Built-in matchers store the route itself so its metadata is
+	// available from the matcher; custom matchers receive the compiled chain.
+	var err error
+	if inserter, ok := r.matcher.(routeInserter); ok {
+		err = inserter.insert(rt)
+	} else {
+		err = r.matcher.Register(method, pattern, rt.serve)
+	}
+	if err != nil {
+		return fmt.Errorf("cosan: failed to register route %s %s at %s: %w", method, pattern, source, err)
 	}
+
 	r.routes = append(r.routes, rt)
+	if !slices.Contains(r.methods, method) {
+		r.methods = append(r.methods, method)
+	}
 
-	// Register with matcher
-	if err := r.matcher.Register(method, pattern, handler); err != nil {
-		panic("cosan: failed to register route: " + err.Error())
+	return nil
+}
+
+// Reset removes every route and returns the router to its uncompiled
+// state, so the route set (and middleware) can be rebuilt before the next
+// request recompiles it. Middleware, hooks, and options are kept. It is
+// meant for test harnesses and hot reloads, not the request path: requests
+// arriving mid-rebuild may see a partial route set.
+//
+// Reset requires WithDynamicRoutes and a built-in matcher; otherwise it
+// returns ErrResetNotSupported and leaves the router unchanged.
+func (r *router) Reset() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rm, ok := r.matcher.(resettableMatcher)
+	if !r.dynamic || !ok {
+		return ErrResetNotSupported
 	}
+
+	rm.reset()
+	r.routes = make([]*route, 0)
+	r.methods = nil
+	r.compiled = false
+
+	return nil
 }
 
 // ensureCompiled ensures the router is compiled before serving requests.
@@ -296,48 +843,156 @@ func (r *router) ensureCompiled() {
 		panic("cosan: failed to compile router: " + err.Error())
 	}
 
+	r.compileRoutes()
 	r.compiled = true
 }
 
+// compileRoutes builds each route's handler chain once, so requests don't
+// rebuild it. Global middleware wraps the route-scoped middleware, which
+// wraps the route-level wrappers, which wrap the handler (as wrapped by
+// WithHandlerWrapper). The chains for unmatched requests are built too.
+func (r *router) compileRoutes() {
+	for _, rt := range r.routes {
+		r.compileRoute(rt)
+	}
+
+	handler := unsupportedMediaTypeHandler
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i].Process(handler)
+	}
+	r.unsupportedMediaType = handler
+
+	r.compileUnmatched()
+}
+
+// compileRoute builds the handler chain for a single route.
+func (r *router) compileRoute(rt *route) {
+	handler := rt.handler
+	if r.handlerWrapper != nil {
+		handler = r.handlerWrapper(rt.info(), handler)
+	}
+
+	timeout := r.requestTimeout
+	if rt.timeoutSet {
+		timeout = rt.timeout
+	}
+	if timeout > 0 {
+		handler = timeoutHandler(handler, timeout)
+	}
+
+	if rt.rateLimit != nil {
+		handler = rateLimitHandler(handler, rt.rateLimit)
+	}
+
+	if r.deprecationHeaders && rt.metadata != nil && rt.metadata.Deprecated {
+		handler = deprecationHandler(handler, rt.metadata.Sunset)
+	}
+
+	if len(rt.cacheControl) > 0 {
+		handler = cacheControlHandler(handler, rt.cacheControl)
+	}
+
+	if len(rt.produces) > 0 {
+		handler = producesHandler(handler, rt.produces)
+	}
+
+	if r.readinessGate && r.readiness != nil && !rt.ungated {
+		handler = readinessHandler(handler, r)
+	}
+
+	for i := len(rt.middleware) - 1; i >= 0; i-- {
+		handler = rt.middleware[i].Process(handler)
+	}
+
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i].Process(handler)
+	}
+
+	rt.chain = handler
+}
+
 // routerGroup represents a route group with a common prefix.
 type routerGroup struct {
-	router *router
-	prefix string
+	router       *router
+	prefix       string
+	parent       *routerGroup
+	errorHandler ErrorHandler
+	version      string // API version set by Version, inherited by nested groups
+}
+
+// inGroup records the group a route was registered through and applies
+// the group's version.
+func inGroup(g *routerGroup) RouteOption {
+	return func(r *route) {
+		r.group = g
+		if g.version != "" {
+			WithVersion(g.version)(r)
+		}
+	}
+}
+
+// register adds a route under the group's prefix. The group is recorded
+// before the caller's options run.
+func (g *routerGroup) register(method, pattern string, handler HandlerFunc, opts []RouteOption) {
+	g.router.registerRoute(method, joinPath(g.prefix, pattern), handler, append([]RouteOption{inGroup(g)}, opts...)...)
+}
+
+// normalizePrefix returns a group prefix with a leading slash and no
+// trailing slash; the root prefix "/" becomes "".
+func normalizePrefix(prefix string) string {
+	prefix = strings.TrimRight(prefix, "/")
+	if prefix != "" && prefix[0] != '/' {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
+
+// joinPath joins a normalized group prefix and a route pattern with exactly
+// one slash. An empty or "/" pattern maps to the group prefix itself.
+func joinPath(prefix, pattern string) string {
+	pattern = strings.TrimLeft(pattern, "/")
+	if pattern == "" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+	return prefix + "/" + pattern
 }
 
 // GET registers a GET route in the group.
-func (g *routerGroup) GET(pattern string, handler HandlerFunc) {
-	g.router.GET(g.prefix+pattern, handler)
+func (g *routerGroup) GET(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	g.register(http.MethodGet, pattern, handler, opts)
 }
 
 // POST registers a POST route in the group.
-func (g *routerGroup) POST(pattern string, handler HandlerFunc) {
-	g.router.POST(g.prefix+pattern, handler)
+func (g *routerGroup) POST(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	g.register(http.MethodPost, pattern, handler, opts)
 }
 
 // PUT registers a PUT route in the group.
-func (g *routerGroup) PUT(pattern string, handler HandlerFunc) {
-	g.router.PUT(g.prefix+pattern, handler)
+func (g *routerGroup) PUT(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	g.register(http.MethodPut, pattern, handler, opts)
 }
 
 // DELETE registers a DELETE route in the group.
-func (g *routerGroup) DELETE(pattern string, handler HandlerFunc) {
-	g.router.DELETE(g.prefix+pattern, handler)
+func (g *routerGroup) DELETE(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	g.register(http.MethodDelete, pattern, handler, opts)
 }
 
 // PATCH registers a PATCH route in the group.
-func (g *routerGroup) PATCH(pattern string, handler HandlerFunc) {
-	g.router.PATCH(g.prefix+pattern, handler)
+func (g *routerGroup) PATCH(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	g.register(http.MethodPatch, pattern, handler, opts)
 }
 
 // OPTIONS registers an OPTIONS route in the group.
-func (g *routerGroup) OPTIONS(pattern string, handler HandlerFunc) {
-	g.router.OPTIONS(g.prefix+pattern, handler)
+func (g *routerGroup) OPTIONS(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	g.register(http.MethodOptions, pattern, handler, opts)
 }
 
 // HEAD registers a HEAD route in the group.
-func (g *routerGroup) HEAD(pattern string, handler HandlerFunc) {
-	g.router.HEAD(g.prefix+pattern, handler)
+func (g *routerGroup) HEAD(pattern string, handler HandlerFunc, opts ...RouteOption) {
+	g.register(http.MethodHead, pattern, handler, opts)
 }
 
 // Use adds middleware to the group (currently global, will be scoped in Phase 2).
@@ -345,9 +1000,32 @@ func (g *routerGroup) Use(middleware ...Middleware) {
 	g.router.Use(middleware...)
 }
 
+// UsePrepend adds middleware ahead of all global middleware (like Use, it
+// is not scoped to the group).
+func (g *routerGroup) UsePrepend(middleware ...Middleware) {
+	g.router.UsePrepend(middleware...)
+}
+
 // Group creates a nested group.
 func (g *routerGroup) Group(prefix string) Router {
-	return g.router.Group(g.prefix + prefix)
+	return &routerGroup{
+		router:  g.router,
+		prefix:  g.prefix + normalizePrefix(prefix),
+		parent:  g,
+		version: g.version,
+	}
+}
+
+// Version creates a versioned subgroup: routes are prefixed with /version
+// under the group's prefix and tagged WithVersion(version).
+func (g *routerGroup) Version(version string) Router {
+	version = strings.Trim(version, "/")
+	return &routerGroup{
+		router:  g.router,
+		prefix:  g.prefix + normalizePrefix(version),
+		parent:  g,
+		version: version,
+	}
 }
 
 // ServeHTTP implements http.Handler (delegates to parent router).
@@ -360,6 +1038,21 @@ func (g *routerGroup) Listen(addr string) error {
 	return g.router.Listen(addr)
 }
 
+// Serve serves on l (delegates to parent router).
+func (g *routerGroup) Serve(l net.Listener) error {
+	return g.router.Serve(l)
+}
+
+// Shutdown stops the router's servers (delegates to parent router).
+func (g *routerGroup) Shutdown(ctx stdcontext.Context) error {
+	return g.router.Shutdown(ctx)
+}
+
+// ListenTLS starts the HTTPS server (delegates to parent router).
+func (g *routerGroup) ListenTLS(addr, certFile, keyFile string) error {
+	return g.router.ListenTLS(addr, certFile, keyFile)
+}
+
 // BeforeRequest delegates to parent router.
 func (g *routerGroup) BeforeRequest(hook RequestHook) {
 	g.router.BeforeRequest(hook)
@@ -370,9 +1063,82 @@ func (g *routerGroup) AfterResponse(hook ResponseHook) {
 	g.router.AfterResponse(hook)
 }
 
-// SetErrorHandler delegates to parent router.
+// Fallback delegates to parent router; the fallback applies to every
+// unmatched request, not only those under the group's prefix.
+func (g *routerGroup) Fallback(handler HandlerFunc) {
+	g.router.Fallback(handler)
+}
+
+// OnError delegates to parent router; observers see errors from every route.
+func (g *routerGroup) OnError(observer ErrorHandler) {
+	g.router.OnError(observer)
+}
+
+// SetErrorHandler sets the error handler for routes registered through the
+// group and its nested groups. Routes outside the group keep using the
+// router-level handler.
 func (g *routerGroup) SetErrorHandler(handler ErrorHandler) {
-	g.router.SetErrorHandler(handler)
+	g.router.mu.Lock()
+	defer g.router.mu.Unlock()
+
+	g.errorHandler = handler
+}
+
+// Health registers a readiness endpoint in the group.
+func (g *routerGroup) Health(path string, checks ...HealthCheck) {
+	g.GET(path, healthHandler(g.router, checks), ungated())
+}
+
+// Liveness registers a liveness endpoint in the group.
+func (g *routerGroup) Liveness(path string) {
+	g.GET(path, livenessHandler, ungated())
+}
+
+// Static serves files under root at prefix within the group.
+func (g *routerGroup) Static(prefix, root string) {
+	g.GET(normalizePrefix(prefix)+"/*"+staticParam, staticHandler(root), untimed())
+}
+
+// Reset delegates to parent router, resetting every route.
+func (g *routerGroup) Reset() error {
+	return g.router.Reset()
+}
+
+// Mount serves every request under prefix within the group with handler,
+// stripping the group prefix as well as prefix.
+func (g *routerGroup) Mount(prefix string, handler http.Handler, opts ...MountOption) {
+	prefix = normalizePrefix(prefix)
+	register := func(method, pattern string, handler HandlerFunc, opts ...RouteOption) {
+		g.register(method, pattern, handler, opts)
+	}
+	mountRoutes(register, prefix, g.prefix+prefix, handler, opts)
+}
+
+// SPA serves a single-page application from root at prefix within the group.
+func (g *routerGroup) SPA(prefix, root, indexFile string) {
+	registerSPA(g.GET, normalizePrefix(prefix), root, indexFile)
+}
+
+// NotFound sets the handler for unmatched requests under the group prefix.
+// The group with the longest matching prefix wins; paths outside every
+// group use the router's handler. On a group without a prefix it sets the
+// router's handler.
+func (g *routerGroup) NotFound(handler HandlerFunc) {
+	if g.prefix == "" {
+		g.router.SetNotFoundHandler(handler)
+		return
+	}
+	g.router.setPrefixNotFound(g.prefix, handler)
+}
+
+// SetNotFoundHandler delegates to parent router.
+func (g *routerGroup) SetNotFoundHandler(handler HandlerFunc) {
+	g.router.SetNotFoundHandler(handler)
+}
+
+// Walk delegates to parent router.
+func (g *routerGroup) Walk(fn WalkFunc) error {
+	return g.router.Walk(fn)
 }
 
 // GetRoutes delegates to parent router.
@@ -380,7 +1146,22 @@ func (g *routerGroup) GetRoutes() []RouteInfo {
 	return g.router.GetRoutes()
 }
 
+// GetRoutesSorted delegates to parent router.
+func (g *routerGroup) GetRoutesSorted() []RouteInfo {
+	return g.router.GetRoutesSorted()
+}
+
+// DumpRoutes delegates to parent router.
+func (g *routerGroup) DumpRoutes(w io.Writer) error {
+	return g.router.DumpRoutes(w)
+}
+
 // FindRoute delegates to parent router.
 func (g *routerGroup) FindRoute(name string) *RouteInfo {
 	return g.router.FindRoute(name)
 }
+
+// MethodsFor delegates to parent router; path is not relative to the group.
+func (g *routerGroup) MethodsFor(path string) []string {
+	return g.router.MethodsFor(path)
+}
@@ -2,12 +2,18 @@ package cosan_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	cosan "github.com/toutaio/toutago-cosan-router"
 )
@@ -250,6 +256,21 @@ func TestRouteConflictDetection(t *testing.T) {
 	})
 }
 
+// TestRouteConflictDetection_Source tests that the duplicate panic names
+// both registration sites, including registration through a group.
+func TestRouteConflictDetection_Source(t *testing.T) {
+	defer func() {
+		msg := fmt.Sprint(recover())
+		if strings.Count(msg, "router_test.go:") != 2 {
+			t.Errorf("Expected both registration sites in panic, got %q", msg)
+		}
+	}()
+
+	router := cosan.New()
+	router.GET("/api/users", func(ctx cosan.Context) error { return nil })
+	router.Group("/api").GET("/users", func(ctx cosan.Context) error { return nil })
+}
+
 // TestRouteGroups tests route grouping functionality.
 func TestRouteGroups(t *testing.T) {
 	router := cosan.New()
@@ -305,6 +326,114 @@ func TestRouteGroups(t *testing.T) {
 	}
 }
 
+// TestRouteGroups_PathJoining tests how group prefixes and patterns are joined.
+func TestRouteGroups_PathJoining(t *testing.T) {
+	noop := func(ctx cosan.Context) error { return nil }
+
+	tests := []struct {
+		name    string
+		prefix  string
+		nested  string
+		pattern string
+		want    string
+	}{
+		{"missing slash on pattern", "/api", "", "users", "/api/users"},
+		{"leading slash on pattern", "/api", "", "/users", "/api/users"},
+		{"trailing slash on prefix", "/api/", "", "/users", "/api/users"},
+		{"missing slash on prefix", "api", "", "users", "/api/users"},
+		{"nested trailing and leading slashes", "/api/", "/v1/", "/users", "/api/v1/users"},
+		{"nested missing slash", "/api", "v1", "users", "/api/v1/users"},
+		{"empty pattern is group root", "/api", "", "", "/api"},
+		{"root group", "/", "", "/users", "/users"},
+		{"root group empty pattern", "", "", "", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := cosan.New()
+			group := router.Group(tt.prefix)
+			if tt.nested != "" {
+				group = group.Group(tt.nested)
+			}
+			group.GET(tt.pattern, noop)
+
+			routes := router.GetRoutes()
+			if len(routes) != 1 || routes[0].Pattern != tt.want {
+				t.Errorf("Expected pattern %q, got %+v", tt.want, routes)
+			}
+		})
+	}
+}
+
+// TestRouteGroups_EmptyPattern tests that "" and "/" register the group root.
+func TestRouteGroups_EmptyPattern(t *testing.T) {
+	router := cosan.New()
+	users := router.Group("/api").Group("/v1").Group("/users")
+	users.POST("", func(ctx cosan.Context) error {
+		return ctx.String(201, "create")
+	})
+	users.GET("/", func(ctx cosan.Context) error {
+		return ctx.String(200, "list")
+	})
+	users.GET("/:id", func(ctx cosan.Context) error {
+		return ctx.String(200, "show "+ctx.Param("id"))
+	})
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{http.MethodPost, "/api/v1/users", 201, "create"},
+		{http.MethodGet, "/api/v1/users", 200, "list"},
+		{http.MethodGet, "/api/v1/users/42", 200, "show 42"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode || w.Body.String() != tt.wantBody {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.wantCode, tt.wantBody, w.Code, w.Body.String())
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected \"\" and \"/\" to register the same route")
+		}
+	}()
+	users.POST("/", func(ctx cosan.Context) error { return nil })
+}
+
+// TestUsePrepend tests that prepended middleware runs first.
+func TestUsePrepend(t *testing.T) {
+	var order []string
+	mark := func(name string) cosan.Middleware {
+		return cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+			return func(ctx cosan.Context) error {
+				order = append(order, name)
+				return next(ctx)
+			}
+		})
+	}
+
+	router := cosan.New()
+	router.Use(mark("logger"), mark("auth"))
+	router.UsePrepend(mark("recovery"), mark("tracing"))
+	router.Group("/api").UsePrepend(mark("first"))
+	router.GET("/", func(ctx cosan.Context) error { return ctx.String(200, "ok") })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"first", "recovery", "tracing", "logger", "auth"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected middleware order %v, got %v", want, order)
+	}
+}
+
 // TestContextValueStorage tests context value storage.
 func TestContextValueStorage(t *testing.T) {
 	router := cosan.New()
@@ -433,3 +562,290 @@ func BenchmarkJSONResponse(b *testing.B) {
 		router.ServeHTTP(w, req)
 	}
 }
+
+// TestBuiltinRecovery tests that handler panics become 500 responses.
+func TestBuiltinRecovery(t *testing.T) {
+	router := cosan.New()
+	router.GET("/panic", func(ctx cosan.Context) error {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != 500 {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "goroutine") {
+		t.Errorf("Stack trace leaked into response: %q", w.Body.String())
+	}
+	if w.Body.String() != "Internal Server Error" {
+		t.Errorf("Expected a plain 500 without the panic value, got %q", w.Body.String())
+	}
+
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "boom") {
+		t.Errorf("Panic value leaked into JSON response: %q", w.Body.String())
+	}
+}
+
+// TestBuiltinRecoveryErrorHandler tests that panics reach the error handler.
+func TestBuiltinRecoveryErrorHandler(t *testing.T) {
+	router := cosan.New()
+	var got *cosan.PanicError
+	router.SetErrorHandler(func(ctx cosan.Context, err error) {
+		errors.As(err, &got)
+		_ = ctx.String(500, "custom")
+	})
+	router.GET("/panic", func(ctx cosan.Context) error {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got == nil || got.Value != "boom" {
+		t.Errorf("Expected *PanicError with value 'boom', got %v", got)
+	}
+	if w.Body.String() != "custom" {
+		t.Errorf("Expected custom error body, got %q", w.Body.String())
+	}
+}
+
+// TestRecoveryDisabled tests that panics propagate when recovery is off.
+func TestRecoveryDisabled(t *testing.T) {
+	router := cosan.New(cosan.WithRecovery(false))
+	router.GET("/panic", func(ctx cosan.Context) error {
+		panic("boom")
+	})
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("Expected panic 'boom' to propagate, got %v", r)
+		}
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+// TestDynamicRoutes tests registering routes after the first request.
+func TestDynamicRoutes(t *testing.T) {
+	router := cosan.New(cosan.WithDynamicRoutes(true))
+	router.Use(cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+		return func(ctx cosan.Context) error {
+			ctx.Header().Set("X-Middleware", "applied")
+			return next(ctx)
+		}
+	}))
+	router.GET("/first", func(ctx cosan.Context) error {
+		return ctx.String(200, "first")
+	})
+
+	// First request compiles the router
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/first", nil))
+
+	router.GET("/plugins/:name", func(ctx cosan.Context) error {
+		return ctx.String(200, "plugin "+ctx.Param("name"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/plugins/search", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != 200 || w.Body.String() != "plugin search" {
+		t.Errorf("Expected dynamic route to match, got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Middleware") != "applied" {
+		t.Error("Expected global middleware on dynamically added route")
+	}
+}
+
+// TestStaticRoutesAfterCompilePanics tests the default immutable behavior.
+func TestStaticRoutesAfterCompilePanics(t *testing.T) {
+	router := cosan.New()
+	router.GET("/first", func(ctx cosan.Context) error { return nil })
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/first", nil))
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic when registering after compile without dynamic routes")
+		}
+	}()
+	router.GET("/second", func(ctx cosan.Context) error { return nil })
+}
+
+// TestMaxPathLength tests that oversized paths are rejected before matching.
+func TestMaxPathLength(t *testing.T) {
+	handler := func(ctx cosan.Context) error { return ctx.String(200, "ok") }
+	long := "/files/" + strings.Repeat("a", cosan.DefaultMaxPathLength)
+
+	tests := []struct {
+		name     string
+		opts     []cosan.Option
+		path     string
+		wantCode int
+	}{
+		{"default limit", nil, long, http.StatusRequestURITooLong},
+		{"within custom limit", []cosan.Option{cosan.WithMaxPathLength(16)}, "/files/short", 200},
+		{"over custom limit", []cosan.Option{cosan.WithMaxPathLength(16)}, "/files/much-too-long", http.StatusRequestURITooLong},
+		{"disabled", []cosan.Option{cosan.WithMaxPathLength(0)}, long, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := cosan.New(tt.opts...)
+			router.GET("/files/:name", handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequireResponse(t *testing.T) {
+	handlers := map[string]cosan.HandlerFunc{
+		"/silent":  func(ctx cosan.Context) error { return nil },
+		"/written": func(ctx cosan.Context) error { return ctx.String(200, "ok") },
+		"/header":  func(ctx cosan.Context) error { ctx.Response().WriteHeader(204); return nil },
+	}
+
+	tests := []struct {
+		name     string
+		require  bool
+		path     string
+		wantCode int
+		wantWarn bool
+	}{
+		{"silent handler, default", false, "/silent", 200, false},
+		{"silent handler, required", true, "/silent", 500, true},
+		{"written body, required", true, "/written", 200, false},
+		{"written status only, required", true, "/header", 204, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			var handled error
+			router := cosan.New(
+				cosan.WithRequireResponse(tt.require),
+				cosan.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+			)
+			router.OnError(func(ctx cosan.Context, err error) { handled = err })
+			for path, h := range handlers {
+				router.GET(path, h)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if warned := strings.Contains(logs.String(), "without writing a response"); warned != tt.wantWarn {
+				t.Errorf("Expected warning %v, got log %q", tt.wantWarn, logs.String())
+			}
+			if tt.wantWarn && !errors.Is(handled, cosan.ErrNoResponse) {
+				t.Errorf("Expected ErrNoResponse, got %v", handled)
+			}
+			if logs.Len() > 0 && !strings.Contains(logs.String(), "route="+tt.path) {
+				t.Errorf("Expected the warning to name the route, got %q", logs.String())
+			}
+		})
+	}
+}
+
+// TestReset tests rebuilding the route set after the router has served.
+func TestReset(t *testing.T) {
+	router := cosan.New(cosan.WithDynamicRoutes(true))
+	router.GET("/old", func(ctx cosan.Context) error {
+		return ctx.String(200, "old")
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	if w := serve("/old"); w.Code != 200 {
+		t.Fatalf("Expected 200 before reset, got %d", w.Code)
+	}
+
+	if err := router.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+
+	// Middleware and re-registration are allowed again after Reset
+	router.Use(cosan.MiddlewareFunc(func(next cosan.HandlerFunc) cosan.HandlerFunc {
+		return func(ctx cosan.Context) error {
+			ctx.Header().Set("X-Reset", "1")
+			return next(ctx)
+		}
+	}))
+	router.GET("/old", func(ctx cosan.Context) error {
+		return ctx.String(200, "new")
+	})
+
+	if w := serve("/old"); w.Body.String() != "new" || w.Header().Get("X-Reset") != "1" {
+		t.Errorf("Expected rebuilt route with middleware, got %q (X-Reset=%q)", w.Body.String(), w.Header().Get("X-Reset"))
+	}
+	if routes := router.GetRoutes(); len(routes) != 1 {
+		t.Errorf("Expected 1 route after reset, got %d", len(routes))
+	}
+}
+
+// TestReset_RequiresDynamicRoutes tests that Reset is refused by default.
+func TestReset_RequiresDynamicRoutes(t *testing.T) {
+	router := cosan.New()
+	if err := router.Reset(); !errors.Is(err, cosan.ErrResetNotSupported) {
+		t.Errorf("Expected ErrResetNotSupported, got %v", err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	router := cosan.New()
+	router.GET("/ping", func(ctx cosan.Context) error {
+		return ctx.String(200, "pong")
+	})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	served := make(chan error, 1)
+	go func() { served <- router.Serve(l) }()
+
+	res, err := http.Get("http://" + l.Addr().String() + "/ping")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != 200 || string(body) != "pong" {
+		t.Errorf("Expected 200 pong, got %d %q", res.StatusCode, body)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := router.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	select {
+	case err := <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("Expected http.ErrServerClosed, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after Shutdown")
+	}
+}
@@ -0,0 +1,29 @@
+package cosan
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// packagePrefix prefixes the function names of this package's frames.
+var packagePrefix = reflect.TypeOf(router{}).PkgPath() + "."
+
+// registrationSource returns the file:line of the code registering a route:
+// the first caller outside this package, so registration through groups and
+// helpers such as Health points at the application. It only runs at
+// registration, never per request.
+func registrationSource() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) || strings.HasSuffix(frame.File, "_test.go") {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
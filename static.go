@@ -0,0 +1,226 @@
+package cosan
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+)
+
+// precompressedVariants are the file suffixes served for each content
+// coding by WithPrecompressedStatic, in order of preference.
+var precompressedVariants = []struct{ encoding, suffix string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// WithPrecompressedStatic makes Static, SPA, and Context.File serve a
+// pre-compressed variant of a file when one exists beside it and the
+// client accepts its encoding: app.js.br for br, then app.js.gz for gzip.
+// The variant is sent with Content-Encoding and the original file's
+// Content-Type, so assets compressed at build time are never compressed
+// at runtime. Responses carry Vary: Accept-Encoding. Disabled by default.
+func WithPrecompressedStatic(enabled bool) Option {
+	return func(r *router) {
+		r.precompressed = enabled
+	}
+}
+
+// File streams the named file from disk with http.ServeContent, which sets
+// Content-Type, Last-Modified, and ETag-based conditional responses, and
+// answers Range requests with 206 Partial Content. The file is never read
+// into memory as a whole. A missing file yields an *HTTPError 404.
+func (c *context) File(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fileError(err)
+	}
+	defer f.Close()
+
+	return serveFile(c, f, name, openFile)
+}
+
+// openFile opens a file on disk as an http.File.
+func openFile(name string) (http.File, error) {
+	return os.Open(name)
+}
+
+// serveFile serves an open file, refusing directories. open reopens
+// siblings of name, the path f was opened by, to find pre-compressed
+// variants.
+func serveFile(ctx Context, f http.File, name string, open func(name string) (http.File, error)) error {
+	info, err := f.Stat()
+	if err != nil {
+		return fileError(err)
+	}
+	if info.IsDir() {
+		return NewHTTPError(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+	}
+
+	if c, ok := unwrapContext(ctx); ok && c.router != nil && c.router.precompressed {
+		ctx.Response().Header().Add("Vary", "Accept-Encoding")
+		if variant, encoding := openPrecompressed(ctx.Request(), name, open); variant != nil {
+			defer variant.Close()
+
+			// The original name and modification time describe the content;
+			// the variant only changes its encoding
+			ctx.Response().Header().Set("Content-Encoding", encoding)
+			http.ServeContent(ctx.Response(), ctx.Request(), info.Name(), info.ModTime(), variant)
+			return nil
+		}
+	}
+
+	http.ServeContent(ctx.Response(), ctx.Request(), info.Name(), info.ModTime(), f)
+	return nil
+}
+
+// openPrecompressed opens the most preferred pre-compressed variant of name
+// that exists and whose encoding the request accepts, returning it and its
+// content coding, or nil if there is none.
+func openPrecompressed(req *http.Request, name string, open func(name string) (http.File, error)) (http.File, string) {
+	accept := req.Header.Get("Accept-Encoding")
+	if accept == "" {
+		return nil, ""
+	}
+
+	ranges := parseAccept(accept)
+	for _, v := range precompressedVariants {
+		if acceptEncodingQuality(ranges, v.encoding) <= 0 {
+			continue
+		}
+
+		f, err := open(name + v.suffix)
+		if err != nil {
+			continue
+		}
+		if info, err := f.Stat(); err != nil || info.IsDir() {
+			f.Close()
+			continue
+		}
+		return f, v.encoding
+	}
+
+	return nil, ""
+}
+
+// acceptEncodingQuality returns the q-value an Accept-Encoding header gives
+// encoding: its own entry if listed, otherwise the * entry, otherwise 0.
+func acceptEncodingQuality(ranges []acceptRange, encoding string) float64 {
+	q := 0.0
+	for _, r := range ranges {
+		switch r.mediaType {
+		case encoding:
+			return r.q
+		case "*":
+			q = r.q
+		}
+	}
+	return q
+}
+
+// fileError maps file system errors to HTTP errors.
+func fileError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return NewHTTPError(http.StatusNotFound, http.StatusText(http.StatusNotFound))
+	case errors.Is(err, fs.ErrPermission):
+		return NewHTTPError(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+	}
+	return err
+}
+
+// staticParam is the wildcard parameter of routes registered by Static.
+const staticParam = "filepath"
+
+// Static serves the files under root at prefix, e.g. Static("/assets",
+// "./public") serves ./public/app.js at /assets/app.js. Files are served
+// like Context.File, including Range requests; a directory serves its
+// index.html. Paths cannot escape root.
+func (r *router) Static(prefix, root string) {
+	r.GET(normalizePrefix(prefix)+"/*"+staticParam, staticHandler(root), untimed())
+}
+
+// staticHandler serves files from root named by the wildcard parameter.
+func staticHandler(root string) HandlerFunc {
+	dir := http.Dir(root)
+
+	return func(ctx Context) error {
+		f, name, err := openStatic(dir, ctx.Param(staticParam))
+		if err != nil {
+			return fileError(err)
+		}
+		defer f.Close()
+
+		return serveFile(ctx, f, name, dir.Open)
+	}
+}
+
+// openStatic opens the named file under dir, or a directory's index.html,
+// returning the cleaned name of the file opened. http.Dir cleans the name,
+// so ".." cannot climb above dir.
+func openStatic(dir http.Dir, name string) (http.File, string, error) {
+	name = path.Clean("/" + name)
+
+	f, err := dir.Open(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		f.Close()
+		name = path.Join(name, "index.html")
+		f, err = dir.Open(name)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	return f, name, nil
+}
+
+// SPA serves a single-page application from root at prefix. Existing files
+// are served like Static; any other path without a file extension serves
+// indexFile (e.g. "index.html") so client-side routing works on deep links
+// and reloads. Missing assets with an extension (.js, .css, .png) still
+// respond 404 rather than returning HTML in their place. Paths cannot
+// escape root.
+//
+// Example:
+//
+//	router.GET("/api/users", ListUsers)
+//	router.SPA("/", "./dist", "index.html")
+//
+// More specific routes, such as an API under /api, take precedence.
+func (r *router) SPA(prefix, root, indexFile string) {
+	registerSPA(r.GET, normalizePrefix(prefix), root, indexFile)
+}
+
+// registerSPA registers the SPA handler for prefix and everything below it.
+func registerSPA(get func(pattern string, handler HandlerFunc, opts ...RouteOption), prefix, root, indexFile string) {
+	handler := spaHandler(root, indexFile)
+	get(joinPath(prefix, ""), handler, untimed())
+	get(joinPath(prefix, "*"+staticParam), handler, untimed())
+}
+
+// spaHandler serves files from root, falling back to indexFile for
+// extensionless paths.
+func spaHandler(root, indexFile string) HandlerFunc {
+	dir := http.Dir(root)
+
+	return func(ctx Context) error {
+		name := ctx.Param(staticParam)
+
+		f, opened, err := openStatic(dir, name)
+		if errors.Is(err, fs.ErrNotExist) && path.Ext(name) == "" {
+			opened = path.Clean("/" + indexFile)
+			f, err = dir.Open(opened)
+		}
+		if err != nil {
+			return fileError(err)
+		}
+		defer f.Close()
+
+		return serveFile(ctx, f, opened, dir.Open)
+	}
+}
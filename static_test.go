@@ -0,0 +1,247 @@
+package cosan
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile creates a file under dir, creating parent directories.
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestFile_Range(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	name := writeFile(t, t.TempDir(), "media.bin", data)
+
+	r := New()
+	r.GET("/media", func(ctx Context) error {
+		return ctx.File(name)
+	})
+
+	req := httptest.NewRequest("GET", "/media", nil)
+	req.Header.Set("Range", "bytes=0-99")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 206 {
+		t.Fatalf("Expected 206, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Range"); got != "bytes 0-99/1000" {
+		t.Errorf("Expected Content-Range bytes 0-99/1000, got %q", got)
+	}
+	if !bytes.Equal(w.Body.Bytes(), data[:100]) {
+		t.Errorf("Expected the first 100 bytes, got %d bytes", w.Body.Len())
+	}
+}
+
+func TestFile_NotFound(t *testing.T) {
+	r := New()
+	r.GET("/missing", func(ctx Context) error {
+		return ctx.File(filepath.Join(t.TempDir(), "missing.txt"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+
+	if w.Code != 404 {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}
+
+func TestStatic(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "public")
+	writeFile(t, root, "app.js", []byte("console.log(1)"))
+	writeFile(t, root, "docs/index.html", []byte("<h1>docs</h1>"))
+	writeFile(t, base, "secret.txt", []byte("secret"))
+
+	r := New()
+	r.Static("/assets", root)
+	r.Group("/v1").Static("files/", root)
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/assets/app.js", 200, "console.log(1)"},
+		{"/assets/docs", 200, "<h1>docs</h1>"},
+		{"/assets/missing.js", 404, ""},
+		{"/assets/../secret.txt", 404, ""},
+		{"/v1/files/app.js", 200, "console.log(1)"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.code, w.Code)
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestStatic_Range(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "video.mp4", bytes.Repeat([]byte("v"), 500))
+
+	r := New()
+	r.Static("/media", root)
+
+	req := httptest.NewRequest("GET", "/media/video.mp4", nil)
+	req.Header.Set("Range", "bytes=100-199")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 206 || w.Body.Len() != 100 {
+		t.Errorf("Expected 206 with 100 bytes, got %d with %d bytes", w.Code, w.Body.Len())
+	}
+	if got := w.Header().Get("Content-Range"); got != "bytes 100-199/500" {
+		t.Errorf("Expected Content-Range bytes 100-199/500, got %q", got)
+	}
+}
+
+func TestSPA(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "dist")
+	writeFile(t, root, "index.html", []byte("<app>"))
+	writeFile(t, root, "assets/app.js", []byte("bundle"))
+	writeFile(t, base, "secret.txt", []byte("secret"))
+
+	r := New()
+	r.GET("/api/users", func(ctx Context) error {
+		return ctx.String(200, "users")
+	})
+	r.SPA("/", root, "index.html")
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/", 200, "<app>"},
+		{"/assets/app.js", 200, "bundle"},
+		{"/dashboard/settings", 200, "<app>"},
+		{"/assets/missing.js", 404, ""},
+		{"/styles/missing.css", 404, ""},
+		{"/api/users", 200, "users"},
+		{"/../secret.txt", 404, ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.code, w.Code)
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestSPA_Prefix(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "index.html", []byte("<app>"))
+
+	r := New()
+	r.Group("/admin").SPA("/ui", root, "index.html")
+
+	for _, p := range []string{"/admin/ui", "/admin/ui/users/42"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", p, nil))
+		if w.Code != 200 || w.Body.String() != "<app>" {
+			t.Errorf("%s: expected index, got %d %q", p, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestWithPrecompressedStatic(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "app.js", []byte("console.log('plain')"))
+	writeFile(t, root, "app.js.gz", []byte("gzip bytes"))
+	writeFile(t, root, "app.js.br", []byte("brotli bytes"))
+	writeFile(t, root, "style.css", []byte("body{}"))
+	writeFile(t, root, "style.css.gz", []byte("gzip css"))
+	writeFile(t, root, "logo.svg", []byte("<svg/>"))
+	name := writeFile(t, root, "report.txt", []byte("plain report"))
+	writeFile(t, root, "report.txt.gz", []byte("gzip report"))
+
+	r := New(WithPrecompressedStatic(true))
+	r.Static("/assets", root)
+	r.GET("/report", func(ctx Context) error {
+		return ctx.File(name)
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		accept   string
+		body     string
+		encoding string
+		ctype    string
+	}{
+		{"br present", "/assets/app.js", "gzip, br", "brotli bytes", "br", "text/javascript; charset=utf-8"},
+		{"gz preferred when br refused", "/assets/app.js", "gzip, br;q=0", "gzip bytes", "gzip", "text/javascript; charset=utf-8"},
+		{"gz present", "/assets/style.css", "gzip, br", "gzip css", "gzip", "text/css; charset=utf-8"},
+		{"neither present", "/assets/logo.svg", "gzip, br", "<svg/>", "", "image/svg+xml"},
+		{"not accepted", "/assets/app.js", "", "console.log('plain')", "", "text/javascript; charset=utf-8"},
+		{"identity only", "/assets/app.js", "identity", "console.log('plain')", "", "text/javascript; charset=utf-8"},
+		{"wildcard", "/assets/style.css", "*", "gzip css", "gzip", "text/css; charset=utf-8"},
+		{"File", "/report", "gzip", "gzip report", "gzip", "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Encoding", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != 200 || w.Body.String() != tt.body {
+				t.Fatalf("Expected 200 %q, got %d %q", tt.body, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Errorf("Expected Content-Encoding %q, got %q", tt.encoding, got)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.ctype {
+				t.Errorf("Expected Content-Type %q, got %q", tt.ctype, got)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Expected Vary Accept-Encoding, got %q", got)
+			}
+		})
+	}
+
+	// Disabled by default: the uncompressed file is served
+	plain := New()
+	plain.Static("/assets", root)
+
+	req := httptest.NewRequest("GET", "/assets/app.js", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	w := httptest.NewRecorder()
+	plain.ServeHTTP(w, req)
+
+	if w.Body.String() != "console.log('plain')" || w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected the uncompressed file without the option, got %q (encoding %q)", w.Body.String(), w.Header().Get("Content-Encoding"))
+	}
+}
@@ -0,0 +1,58 @@
+package cosan
+
+import (
+	"fmt"
+	"io"
+)
+
+// StreamArray writes items as a JSON array while they arrive, so large
+// lists (e.g. rows from a database cursor) are sent with constant memory.
+// The status and headers are written immediately and the array is closed
+// when items is closed; an empty stream produces []. Buffered items are
+// flushed whenever the producer falls behind, so clients see them promptly.
+//
+// If the request is canceled, StreamArray stops and returns the context's
+// error. If an item fails to encode, it returns the error with the array
+// left unterminated, since the response is already committed.
+func (c *context) StreamArray(code int, items <-chan interface{}) error {
+	c.res.Header().Set("Content-Type", "application/json")
+	c.res.WriteHeader(code)
+	if _, err := io.WriteString(c.res, "["); err != nil {
+		return err
+	}
+
+	codec := c.jsonCodec()
+	done := c.req.Context().Done()
+	for n := 0; ; n++ {
+		var item interface{}
+		var ok bool
+		select {
+		case item, ok = <-items:
+		default:
+			// Nothing ready: send what is buffered before waiting
+			_ = c.Flush()
+			select {
+			case item, ok = <-items:
+			case <-done:
+				return c.Err()
+			}
+		}
+		if !ok {
+			_, err := io.WriteString(c.res, "]\n")
+			return err
+		}
+
+		body, err := codec.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		if n > 0 {
+			if _, err := io.WriteString(c.res, ","); err != nil {
+				return err
+			}
+		}
+		if _, err := c.res.Write(body); err != nil {
+			return err
+		}
+	}
+}
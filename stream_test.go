@@ -0,0 +1,87 @@
+package cosan
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_StreamArray(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []interface{}
+		want  string
+	}{
+		{"empty", nil, "[]\n"},
+		{"one", []interface{}{1}, "[1]\n"},
+		{"many", []interface{}{map[string]int{"id": 1}, "two", nil}, "[{\"id\":1},\"two\",null]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx := newContext(w, httptest.NewRequest("GET", "/rows", nil), nil)
+
+			items := make(chan interface{})
+			go func() {
+				defer close(items)
+				for _, item := range tt.items {
+					items <- item
+				}
+			}()
+
+			if err := ctx.StreamArray(200, items); err != nil {
+				t.Fatalf("StreamArray failed: %v", err)
+			}
+			if w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+			if w.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("Unexpected Content-Type %q", w.Header().Get("Content-Type"))
+			}
+			if len(tt.items) > 0 && !w.Flushed {
+				t.Error("Expected the stream to flush while waiting for items")
+			}
+		})
+	}
+}
+
+func TestContext_StreamArray_Large(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "/rows", nil), nil)
+
+	items := make(chan interface{}, 64)
+	go func() {
+		defer close(items)
+		for i := 0; i < 5000; i++ {
+			items <- i
+		}
+	}()
+
+	if err := ctx.StreamArray(200, items); err != nil {
+		t.Fatalf("StreamArray failed: %v", err)
+	}
+
+	var got []int
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON array: %v", err)
+	}
+	if len(got) != 5000 || got[4999] != 4999 {
+		t.Errorf("Expected 5000 items in order, got %d", len(got))
+	}
+}
+
+func TestContext_StreamArray_Canceled(t *testing.T) {
+	reqCtx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	req := httptest.NewRequest("GET", "/rows", nil).WithContext(reqCtx)
+	ctx := newContext(httptest.NewRecorder(), req, nil)
+
+	items := make(chan interface{})
+	cancel()
+
+	if err := ctx.StreamArray(200, items); !errors.Is(err, stdcontext.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package cosan
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithTimeout bounds the route's handler to d. The handler runs with a
+// request context that is canceled after d; if it has not returned by then,
+// the error handler receives an *HTTPError with status 504.
+//
+// The handler's response is buffered until it returns, so routes with a
+// timeout cannot stream, flush, or hijack the connection.
+//
+// A handler that outlives its timeout keeps its Context, which is released
+// once it returns, but shares it with the error handler: it should return as
+// soon as its request context is done without writing a response. Writes
+// after the response is complete fail with http.ErrHandlerTimeout.
+//
+// WithTimeout overrides the router's WithRequestTimeout default; d <= 0
+// disables the timeout for the route, which streaming routes need.
+//
+// Example:
+//
+//	router.GET("/report", ReportHandler, cosan.WithTimeout(5*time.Second))
+//	router.GET("/events", EventsHandler, cosan.WithTimeout(0))
+func WithTimeout(d time.Duration) RouteOption {
+	return func(r *route) {
+		r.timeout = d
+		r.timeoutSet = true
+	}
+}
+
+// WithRequestTimeout applies a default timeout of d to every route, as if
+// each were registered with WithTimeout(d). Routes registered with their own
+// WithTimeout keep it. d <= 0 disables the default (the default).
+//
+// Like WithTimeout it buffers each response in memory, so handlers that
+// stream (StreamArray, Flush, Hijack, large downloads) must opt out with
+// WithTimeout(0). Routes registered by Static, SPA, and Mount are exempt.
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithRequestTimeout(30 * time.Second))
+func WithRequestTimeout(d time.Duration) Option {
+	return func(r *router) {
+		r.requestTimeout = d
+	}
+}
+
+// untimed exempts a route from the WithRequestTimeout default, for routes
+// that serve files or mounted handlers and so may stream.
+func untimed() RouteOption {
+	return func(r *route) {
+		if !r.timeoutSet {
+			r.timeout = 0
+			r.timeoutSet = true
+		}
+	}
+}
+
+// timeoutHandler runs next on the request's own Context, with the request
+// context bounded to d and the response buffered by a timeoutWriter so a late
+// handler never writes over the timeout response. The handler goroutine may
+// outlive the request, so a timed-out context is released only once it
+// returns (see releaseContexts).
+func timeoutHandler(next HandlerFunc, d time.Duration) HandlerFunc {
+	return func(ctx Context) error {
+		c, ok := unwrapContext(ctx)
+		if !ok {
+			return next(ctx)
+		}
+
+		reqCtx, cancel := stdcontext.WithTimeout(c.req.Context(), d)
+		defer cancel()
+
+		req, res := c.req, c.res
+		tw := &timeoutWriter{res: res, header: res.Header().Clone(), code: http.StatusOK}
+		c.req = req.WithContext(reqCtx)
+		c.res = tw
+
+		done := make(chan error, 1)
+		panicked := make(chan interface{}, 1)
+		go func() {
+			var err error
+			defer func() {
+				p := recover()
+				tw.handlerReturned()
+				if p != nil {
+					panicked <- p
+					return
+				}
+				done <- err
+			}()
+			err = next(ctx)
+		}()
+
+		select {
+		case p := <-panicked:
+			c.req, c.res = req, res
+			panic(p)
+		case err := <-done:
+			c.req, c.res = req, res
+			if writeErr := tw.flushTo(res); writeErr != nil && err == nil {
+				err = writeErr
+			}
+			return err
+		case <-reqCtx.Done():
+			// The handler still holds c, so c.res stays tw: it now passes
+			// writes through for the error handler, and rejects them once
+			// the response is complete
+			tw.timeout()
+			c.timedOut = tw
+			return &HTTPError{
+				Code:    http.StatusGatewayTimeout,
+				Message: http.StatusText(http.StatusGatewayTimeout),
+				Err:     reqCtx.Err(),
+			}
+		}
+	}
+}
+
+// releaseContexts releases a request's Context and, when a ContextFactory
+// supplied it, hctx. If the route's handler outlived its timeout, both are
+// released by the handler goroutine once it returns instead.
+func (r *router) releaseContexts(ctx *context, hctx Context) {
+	release := func() {
+		r.releaseHandlerContext(hctx)
+		releaseContext(ctx)
+	}
+	if ctx.timedOut != nil && ctx.timedOut.detach(release) {
+		return
+	}
+	release()
+}
+
+// timeoutWriter buffers a handler's response until it completes. After the
+// timeout it writes straight to res, for the error handler, until detach
+// marks the response complete; writes after that fail with
+// http.ErrHandlerTimeout.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	res         http.ResponseWriter
+	header      http.Header
+	buf         bytes.Buffer
+	code        int
+	wroteHeader bool
+	timedOut    bool
+	closed      bool
+	returned    bool   // The handler goroutine has returned
+	release     func() // Set by detach while the handler is still running
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut && !w.closed {
+		return w.res.Header()
+	}
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	if w.timedOut {
+		w.res.WriteHeader(code)
+		return
+	}
+	if w.wroteHeader {
+		return
+	}
+	if code < 100 || code > 999 {
+		panic(fmt.Sprintf("cosan: invalid WriteHeader code %v", code))
+	}
+	w.code = code
+	w.wroteHeader = true
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.timedOut {
+		return w.res.Write(b)
+	}
+	w.wroteHeader = true
+	return w.buf.Write(b)
+}
+
+// timeout discards the buffered response and passes later writes through
+// to res.
+func (w *timeoutWriter) timeout() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.timedOut = true
+	w.buf.Reset()
+}
+
+// detach marks the response complete. If the handler has not returned yet
+// it keeps release for handlerReturned to run and reports true.
+func (w *timeoutWriter) detach(release func()) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.returned {
+		return false
+	}
+	w.release = release
+	return true
+}
+
+// handlerReturned records that the handler goroutine has returned, running
+// the release detach left for it.
+func (w *timeoutWriter) handlerReturned() {
+	w.mu.Lock()
+	w.returned = true
+	release := w.release
+	w.mu.Unlock()
+
+	if release != nil {
+		release()
+	}
+}
+
+// flushTo copies the buffered response to w. Nothing is written when the
+// handler produced no response, leaving it to the error handler.
+func (w *timeoutWriter) flushTo(dst http.ResponseWriter) error {
+	if !w.wroteHeader {
+		return nil
+	}
+
+	header := dst.Header()
+	for k := range header {
+		if _, ok := w.header[k]; !ok {
+			header.Del(k)
+		}
+	}
+	for k, v := range w.header {
+		header[k] = v
+	}
+
+	dst.WriteHeader(w.code)
+	_, err := dst.Write(w.buf.Bytes())
+	return err
+}
@@ -0,0 +1,258 @@
+package cosan
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout_Expires(t *testing.T) {
+	r := New()
+	release := make(chan struct{})
+	finished := make(chan error, 1)
+	r.GET("/report", func(ctx Context) error {
+		<-ctx.Request().Context().Done()
+		<-release
+		_, err := ctx.Write([]byte("too late"))
+		finished <- err
+		return nil
+	}, WithTimeout(20*time.Millisecond))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504, got %d", w.Code)
+	}
+
+	close(release)
+	if err := <-finished; !errors.Is(err, http.ErrHandlerTimeout) {
+		t.Errorf("Expected late write to fail with http.ErrHandlerTimeout, got %v", err)
+	}
+	if w.Body.String() != "Gateway Timeout" {
+		t.Errorf("Unexpected body %q", w.Body.String())
+	}
+}
+
+func TestWithTimeout_ParamsAfterTimeout(t *testing.T) {
+	r := New()
+	release := make(chan struct{})
+	param := make(chan string, 1)
+	r.GET("/reports/:id", func(ctx Context) error {
+		<-ctx.Request().Context().Done()
+		<-release
+		param <- ctx.Param("id")
+		return nil
+	}, WithTimeout(10*time.Millisecond))
+	r.GET("/users/:id", func(ctx Context) error {
+		return ctx.String(200, "%s", ctx.Param("id"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/reports/42", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504, got %d", w.Code)
+	}
+
+	// The request's pooled context has been released and may be reused;
+	// the late handler must still see its own parameters
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/7", nil))
+	close(release)
+	if got := <-param; got != "42" {
+		t.Errorf("Expected param 42 after the timeout, got %q", got)
+	}
+}
+
+func TestWithTimeout_ReleaseWaitsForHandler(t *testing.T) {
+	factory := &countingFactory{}
+	r := New(WithContextFactory(factory))
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.(*appContext).User = "alice"
+			return next(ctx)
+		}
+	}))
+	release := make(chan struct{})
+	user := make(chan string, 1)
+	r.GET("/report", func(ctx Context) error {
+		<-ctx.Request().Context().Done()
+		<-release
+		user <- ctx.(*appContext).User
+		return nil
+	}, WithTimeout(10*time.Millisecond))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504, got %d", w.Code)
+	}
+
+	factory.mu.Lock()
+	released := factory.released
+	factory.mu.Unlock()
+	if released != 0 {
+		t.Errorf("Expected the context to stay acquired while the handler runs, got %d released", released)
+	}
+
+	close(release)
+	if got := <-user; got != "alice" {
+		t.Errorf("Expected the late handler to keep its context, got user %q", got)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		factory.mu.Lock()
+		released = factory.released
+		factory.mu.Unlock()
+		if released == 1 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if released != 1 {
+		t.Errorf("Expected the context to be released once the handler returned, got %d released", released)
+	}
+}
+
+func TestWithTimeout_CompletesInTime(t *testing.T) {
+	r := New()
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.Header().Set("X-Outer", "1")
+			err := next(ctx)
+			if ctx.Request().URL.Path == "/fast" && ctx.Get("inner") != "set" {
+				t.Error("Expected values set by the handler to be visible to middleware")
+			}
+			return err
+		}
+	}))
+	r.GET("/fast", func(ctx Context) error {
+		if _, ok := ctx.Request().Context().Deadline(); !ok {
+			t.Error("Expected request context to carry a deadline")
+		}
+		ctx.Set("inner", "set")
+		return ctx.String(201, "done %s", ctx.Param("x"))
+	}, WithTimeout(time.Second))
+	r.GET("/plain", func(ctx Context) error {
+		if _, ok := ctx.Request().Context().Deadline(); ok {
+			t.Error("Expected no deadline on routes without WithTimeout")
+		}
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+
+	if w.Code != 201 || w.Body.String() != "done " {
+		t.Errorf("Expected 201 'done ', got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Outer") != "1" || w.Header().Get("Content-Type") == "" {
+		t.Errorf("Expected outer and handler headers, got %v", w.Header())
+	}
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/plain", nil))
+}
+
+func TestWithTimeout_Panic(t *testing.T) {
+	r := New()
+	r.GET("/boom", func(ctx Context) error {
+		panic("boom")
+	}, WithTimeout(time.Second))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/boom", nil))
+
+	if w.Code != 500 {
+		t.Errorf("Expected recovered panic to produce 500, got %d", w.Code)
+	}
+}
+
+func TestWithRequestTimeout(t *testing.T) {
+	r := New(WithRequestTimeout(20 * time.Millisecond))
+	r.GET("/slow", func(ctx Context) error {
+		<-ctx.Request().Context().Done()
+		return nil
+	})
+	r.GET("/events", func(ctx Context) error {
+		if _, ok := ctx.Request().Context().Deadline(); ok {
+			t.Error("Expected WithTimeout(0) to disable the default deadline")
+		}
+		return ctx.Flush()
+	}, WithTimeout(0))
+	r.GET("/report", func(ctx Context) error {
+		deadline, ok := ctx.Request().Context().Deadline()
+		if !ok || time.Until(deadline) < 500*time.Millisecond {
+			t.Error("Expected the route timeout to override the default")
+		}
+		return nil
+	}, WithTimeout(time.Second))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+	if w.Code != http.StatusOK || !w.Flushed {
+		t.Errorf("Expected flushed 200 from streaming route, got %d (flushed %v)", w.Code, w.Flushed)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+func TestWithRequestTimeout_Streaming(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "video.mp4", []byte("video"))
+
+	// The wrapper runs inside any timeout, so it sees the handler's deadline
+	deadlines := make(map[string]bool)
+	r := New(WithRequestTimeout(time.Second), WithHandlerWrapper(func(route RouteInfo, next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			_, deadlines[route.Pattern] = ctx.Request().Context().Deadline()
+			return next(ctx)
+		}
+	}))
+	r.Static("/media", root)
+	r.Mount("/legacy", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+		_ = http.NewResponseController(w).Flush()
+	}))
+	r.GET("/events", func(ctx Context) error {
+		_, _ = ctx.Write([]byte("data: 1\n\n"))
+		return ctx.Flush()
+	}, WithTimeout(0))
+	r.GET("/report", func(ctx Context) error {
+		return ctx.String(200, "report")
+	})
+
+	tests := []struct {
+		path     string
+		pattern  string
+		deadline bool
+		flushed  bool
+	}{
+		{"/media/video.mp4", "/media/*filepath", false, false},
+		{"/legacy/feed", "/legacy/*path", false, true},
+		{"/events", "/events", false, true},
+		{"/report", "/report", true, false},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != http.StatusOK || w.Flushed != tt.flushed {
+			t.Errorf("%s: expected 200 (flushed %v), got %d (flushed %v)", tt.path, tt.flushed, w.Code, w.Flushed)
+		}
+		if deadlines[tt.pattern] != tt.deadline {
+			t.Errorf("%s: expected deadline %v, got %v", tt.path, tt.deadline, deadlines[tt.pattern])
+		}
+	}
+}
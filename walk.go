@@ -0,0 +1,81 @@
+package cosan
+
+import (
+	"slices"
+	"sort"
+)
+
+// WalkFunc is called by Router.Walk for each registered route.
+type WalkFunc func(method, pattern string, handler HandlerFunc) error
+
+// treeOrderer is implemented by matchers that can list their routes in
+// lookup order rather than registration order.
+type treeOrderer interface {
+	treeRoutes() []*route
+}
+
+// Walk calls fn for every registered route, stopping at the first error,
+// which it returns. The router is compiled first. Routes are visited by
+// method in alphabetical order; within a method, the radix matcher yields
+// its tree order (static segments before parameters, then wildcards) and
+// other matchers sort by pattern. The handler is the one registered,
+// without middleware.
+//
+// Example:
+//
+//	router.Walk(func(method, pattern string, handler cosan.HandlerFunc) error {
+//	    fmt.Println(method, pattern)
+//	    return nil
+//	})
+func (r *router) Walk(fn WalkFunc) error {
+	r.ensureCompiled()
+
+	for _, rt := range r.walkOrder() {
+		if err := fn(rt.method, rt.pattern, rt.handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkOrder snapshots the routes in Walk order, so callbacks run without
+// holding any lock. Only the lookup order comes from the matcher; the
+// routes are the router's own.
+func (r *router) walkOrder() []*route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if t, ok := r.matcher.(treeOrderer); ok {
+		return withVariants(t.treeRoutes())
+	}
+
+	routes := slices.Clone(r.routes)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].method != routes[j].method {
+			return routes[i].method < routes[j].method
+		}
+		return routes[i].pattern < routes[j].pattern
+	})
+	return routes
+}
+
+// treeRoutes returns the routes of each method's tree in lookup order,
+// with methods sorted alphabetically.
+func (m *radixMatcher) treeRoutes() []*route {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	methods := make([]string, 0, len(m.trees))
+	for method := range m.trees {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var routes []*route
+	seen := make(map[*route]bool)
+	for _, method := range methods {
+		routes = collectRoutes(m.trees[method], routes, seen)
+	}
+	return routes
+}
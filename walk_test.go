@@ -0,0 +1,72 @@
+package cosan
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouter_Walk(t *testing.T) {
+	r := New()
+	handler := func(ctx Context) error { return ctx.String(200, "users") }
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc { return next }))
+	r.POST("/users", handler)
+	r.GET("/users/*rest", handler)
+	r.GET("/users/:id", handler)
+	r.GET("/users/me", handler)
+	r.GET("/users", handler)
+	r.DELETE("/users/:id", handler)
+
+	var visited []string
+	err := r.Walk(func(method, pattern string, h HandlerFunc) error {
+		if h == nil {
+			t.Errorf("%s %s: nil handler", method, pattern)
+		}
+		visited = append(visited, method+" "+pattern)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk returned %v", err)
+	}
+
+	want := []string{
+		"DELETE /users/:id",
+		"GET /users",
+		"GET /users/me",
+		"GET /users/:id",
+		"GET /users/*rest",
+		"POST /users",
+	}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("Walk order:\n got %v\nwant %v", visited, want)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = r.Walk(func(method, pattern string, h HandlerFunc) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("Expected Walk to stop at the first error, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRouter_WalkHashMatcher(t *testing.T) {
+	r := New(WithMatcher(NewHashMatcher()))
+	handler := func(ctx Context) error { return nil }
+	r.GET("/b", handler)
+	r.GET("/a", handler)
+	r.DELETE("/c", handler)
+
+	var visited []string
+	r.Walk(func(method, pattern string, h HandlerFunc) error {
+		visited = append(visited, method+" "+pattern)
+		return nil
+	})
+
+	want := []string{"DELETE /c", "GET /a", "GET /b"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("Walk order:\n got %v\nwant %v", visited, want)
+	}
+}
@@ -0,0 +1,31 @@
+package cosan
+
+// HandlerWrapper wraps a route's handler when the route is compiled. It
+// receives the route's metadata, so instrumentation can be keyed by the
+// pattern, name, or tags without inspecting each request.
+type HandlerWrapper func(route RouteInfo, next HandlerFunc) HandlerFunc
+
+// WithHandlerWrapper wraps every route's handler with wrap, e.g. to add
+// APM timing or tracing spans named after the route. Unlike middleware,
+// wrap runs once per route when its handler chain is built (when the
+// router compiles on its first request, or on registration for routes
+// added afterwards) and sees the route's RouteInfo. After Reset, wrap
+// runs again for each route registered anew, when the router recompiles.
+// The wrapped handler is innermost: middleware, timeouts, and rate limits
+// run outside it.
+//
+// Example:
+//
+//	router := cosan.New(cosan.WithHandlerWrapper(func(route cosan.RouteInfo, next cosan.HandlerFunc) cosan.HandlerFunc {
+//		name := route.Method + " " + route.Pattern
+//		return func(ctx cosan.Context) error {
+//			span := tracer.Start(ctx.Request().Context(), name)
+//			defer span.End()
+//			return next(ctx)
+//		}
+//	}))
+func WithHandlerWrapper(wrap HandlerWrapper) Option {
+	return func(r *router) {
+		r.handlerWrapper = wrap
+	}
+}
@@ -0,0 +1,45 @@
+package cosan
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWithHandlerWrapper(t *testing.T) {
+	var wrapped []RouteInfo
+	r := New(WithHandlerWrapper(func(route RouteInfo, next HandlerFunc) HandlerFunc {
+		wrapped = append(wrapped, route)
+		return func(ctx Context) error {
+			ctx.Header().Set("X-Route", route.Name)
+			return next(ctx)
+		}
+	}))
+	r.Use(MiddlewareFunc(func(next HandlerFunc) HandlerFunc {
+		return func(ctx Context) error {
+			ctx.Header().Set("X-Route", "middleware")
+			return next(ctx)
+		}
+	}))
+
+	handler := func(ctx Context) error { return ctx.String(200, "ok") }
+	r.GET("/users", handler, WithName("users.list"), WithTags("users"))
+	r.Group("/admin").DELETE("/users/:id", handler, WithName("admin.users.delete"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+
+	want := []RouteInfo{
+		{Method: "GET", Pattern: "/users", Name: "users.list", Tags: []string{"users"}},
+		{Method: "DELETE", Pattern: "/admin/users/:id", Name: "admin.users.delete"},
+	}
+	if !reflect.DeepEqual(wrapped, want) {
+		t.Errorf("Expected the wrapper to be called once per route with\n%+v\ngot\n%+v", want, wrapped)
+	}
+
+	// The wrapper is innermost, so it runs after the middleware
+	if got := w.Header().Get("X-Route"); got != "users.list" {
+		t.Errorf("Expected X-Route from the wrapper, got %q", got)
+	}
+}